@@ -0,0 +1,54 @@
+package config
+
+// ConfigVersion is the current supported version of the run configuration file.
+const ConfigVersion = "v1"
+
+// ProductConfig represents the run configuration loaded from config file and env overrides.
+type ProductConfig struct {
+	Version     string        `yaml:"version" json:"version"`
+	Product     string        `yaml:"product" json:"product"`
+	TFVars      string        `yaml:"tfvars" json:"tfvars"`
+	Provisioner string        `yaml:"provisioner" json:"provisioner"`
+	KubeConfig  string        `yaml:"kubeconfig" json:"kubeconfig"`
+	Inventory   string        `yaml:"inventory" json:"inventory"`
+	Datastore   string        `yaml:"datastore" json:"datastore"`
+	ArtifactDir string        `yaml:"artifact_dir" json:"artifact_dir"`
+	SSH         SSHConfig     `yaml:"ssh" json:"ssh"`
+	Timeouts    TimeoutConfig `yaml:"timeouts" json:"timeouts"`
+	Tests       TestConfig    `yaml:"tests" json:"tests"`
+	Upgrade     UpgradeConfig `yaml:"upgrade" json:"upgrade"`
+	Destroy     bool          `yaml:"destroy" json:"destroy"`
+}
+
+// SSHConfig represents the credentials used to reach the nodes.
+type SSHConfig struct {
+	User       string `yaml:"user" json:"user"`
+	Key        string `yaml:"key" json:"key"`
+	KnownHosts string `yaml:"known_hosts" json:"known_hosts"`
+}
+
+// TimeoutConfig represents the timeouts used while waiting on cluster resources.
+type TimeoutConfig struct {
+	Node    string `yaml:"node" json:"node"`
+	Pod     string `yaml:"pod" json:"pod"`
+	Command string `yaml:"command" json:"command"`
+}
+
+// TestConfig represents the test selection, same as the test flags.
+type TestConfig struct {
+	Cases           []string `yaml:"cases" json:"cases"`
+	DeployWorkload  bool     `yaml:"deploy_workload" json:"deploy_workload"`
+	WorkloadName    string   `yaml:"workload_name" json:"workload_name"`
+	Description     string   `yaml:"description" json:"description"`
+	Plan            string   `yaml:"plan" json:"plan"`
+	SonobuoyVersion string   `yaml:"sonobuoy_version" json:"sonobuoy_version"`
+}
+
+// UpgradeConfig represents the upgrade settings, same as the upgrade flags.
+type UpgradeConfig struct {
+	InstallVersionOrCommit string `yaml:"install_version_or_commit" json:"install_version_or_commit"`
+	Channel                string `yaml:"channel" json:"channel"`
+	SUCUpgradeVersion      string `yaml:"suc_upgrade_version" json:"suc_upgrade_version"`
+	BatchSize              int    `yaml:"batch_size" json:"batch_size"`
+	AirgapDir              string `yaml:"airgap_dir" json:"airgap_dir"`
+}
@@ -2,61 +2,262 @@ package config
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
 )
 
 var (
 	product *ProductConfig
+	loadErr error
 	once    sync.Once
 )
 
-type ProductConfig struct {
-	TFVars  string
-	Product string
-}
-
+// AddConfigEnv returns a singleton config loaded from the given yaml, json or .env file.
 func AddConfigEnv(path string) (*ProductConfig, error) {
 	once.Do(func() {
-		var err error
-		product, err = loadEnv(path)
-		if err != nil {
-			return
-		}
+		product, loadErr = loadConfig(path)
 	})
 
-	return product, nil
+	return product, loadErr
 }
 
-func loadEnv(fullPath string) (config *ProductConfig, err error) {
-	if err = setEnv(fullPath); err != nil {
+// loadConfig loads the config file, applies the env overrides and validates it.
+func loadConfig(fullPath string) (config *ProductConfig, err error) {
+	config = &ProductConfig{}
+
+	switch strings.ToLower(filepath.Ext(fullPath)) {
+	case ".yaml", ".yml":
+		err = loadYAML(fullPath, config)
+	case ".json":
+		err = loadJSON(fullPath, config)
+	default:
+		err = setEnv(fullPath)
+		config.Version = ConfigVersion
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	config = &ProductConfig{}
-	config.TFVars = os.Getenv("ENV_TFVARS")
-	config.Product = os.Getenv("ENV_PRODUCT")
+	addEnvOverrides(config)
+	addDefaults(config)
 
-	if config.TFVars == "" || (config.TFVars != "k3s.tfvars" && config.TFVars != "rke2.tfvars") {
-		fmt.Printf("unknown tfvars: %s\n", config.TFVars)
-		os.Exit(1)
+	if err = validate(config, filepath.Dir(fullPath)); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", fullPath, err)
 	}
 
-	if config.Product == "" || (config.Product != "k3s" && config.Product != "rke2") {
-		fmt.Printf("unknown product: %s\n", config.Product)
-		os.Exit(1)
+	return config, nil
+}
+
+func loadYAML(fullPath string, config *ProductConfig) error {
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
 	}
 
-	return config, nil
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", fullPath, err)
+	}
+
+	return nil
+}
+
+func loadJSON(fullPath string, config *ProductConfig) error {
+	content, err := os.ReadFile(fullPath)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(config); err != nil {
+		return fmt.Errorf("failed to parse config file %s: %w", fullPath, err)
+	}
+
+	return nil
+}
+
+// addEnvOverrides overrides the config values with the ENV_ variables when set.
+func addEnvOverrides(config *ProductConfig) {
+	overrides := map[string]*string{
+		"ENV_PRODUCT":         &config.Product,
+		"ENV_TFVARS":          &config.TFVars,
+		"ENV_PROVISIONER":     &config.Provisioner,
+		"ENV_KUBECONFIG":      &config.KubeConfig,
+		"ENV_INVENTORY":       &config.Inventory,
+		"ENV_DATASTORE":       &config.Datastore,
+		"ENV_ARTIFACT_DIR":    &config.ArtifactDir,
+		"ENV_SSH_USER":        &config.SSH.User,
+		"ENV_SSH_KEY":         &config.SSH.Key,
+		"ENV_SSH_KNOWN_HOSTS": &config.SSH.KnownHosts,
+		"ENV_AIRGAP_DIR":      &config.Upgrade.AirgapDir,
+	}
+
+	for key, value := range overrides {
+		if env := os.Getenv(key); env != "" {
+			*value = env
+		}
+	}
+}
+
+func addDefaults(config *ProductConfig) {
+	if config.Provisioner == "" {
+		config.Provisioner = "terraform"
+	}
+	if config.TFVars == "" && config.Provisioner == "terraform" && config.Product != "" {
+		config.TFVars = config.Product + ".tfvars"
+	}
+	if config.Timeouts.Node == "" {
+		config.Timeouts.Node = "1500s"
+	}
+	if config.Timeouts.Pod == "" {
+		config.Timeouts.Pod = "900s"
+	}
+	if config.Timeouts.Command == "" {
+		config.Timeouts.Command = "420s"
+	}
+	if config.Upgrade.BatchSize == 0 {
+		config.Upgrade.BatchSize = 1
+	}
+}
+
+// validate checks the config values returning the first invalid one,
+// reading the tfvars file from dir for the values the cluster is built with.
+func validate(config *ProductConfig, dir string) error {
+	if config.Version != ConfigVersion {
+		return fmt.Errorf("unsupported version: %q, expected: %q", config.Version, ConfigVersion)
+	}
+
+	if config.Product != "k3s" && config.Product != "rke2" {
+		return fmt.Errorf("unknown product: %q, must be k3s or rke2", config.Product)
+	}
+
+	switch config.Provisioner {
+	case "terraform":
+		if config.TFVars != "k3s.tfvars" && config.TFVars != "rke2.tfvars" {
+			return fmt.Errorf("unknown tfvars: %q, must be k3s.tfvars or rke2.tfvars", config.TFVars)
+		}
+	case "existing":
+		if config.KubeConfig == "" || config.Inventory == "" {
+			return fmt.Errorf("kubeconfig and inventory are required for existing provisioner")
+		}
+	default:
+		return fmt.Errorf("unknown provisioner: %q, must be terraform or existing", config.Provisioner)
+	}
+
+	switch config.Datastore {
+	case "", "etcd":
+	case "sqlite", "mysql", "mariadb", "postgres", "aurora-mysql", "mysql-container", "postgres-container":
+		if config.Product != "k3s" {
+			return fmt.Errorf("datastore %q is only supported on k3s", config.Datastore)
+		}
+	default:
+		return fmt.Errorf("unknown datastore: %q, must be etcd, sqlite, mysql, mariadb, postgres, aurora-mysql, "+
+			"mysql-container or postgres-container", config.Datastore)
+	}
+
+	if config.Provisioner == "terraform" {
+		if err := validateSqliteServers(config, filepath.Join(dir, config.TFVars)); err != nil {
+			return err
+		}
+	}
+
+	timeouts := map[string]string{
+		"timeouts.node":    config.Timeouts.Node,
+		"timeouts.pod":     config.Timeouts.Pod,
+		"timeouts.command": config.Timeouts.Command,
+	}
+	for name, value := range timeouts {
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("invalid %s: %q, must be a duration like 300s", name, value)
+		}
+	}
+
+	channel := config.Upgrade.Channel
+	if channel != "" && channel != "latest" && channel != "stable" && channel != "testing" {
+		return fmt.Errorf("invalid upgrade.channel: %q, must be latest, stable or testing", channel)
+	}
+
+	if config.Upgrade.BatchSize < 1 {
+		return fmt.Errorf("invalid upgrade.batch_size: %d, must be at least 1", config.Upgrade.BatchSize)
+	}
+
+	return nil
 }
 
+// validateSqliteServers rejects a sqlite datastore, from config or the tfvars file,
+// with more than one server, since the servers would not share the database.
+func validateSqliteServers(config *ProductConfig, tfvarsPath string) error {
+	vars, err := readTFVars(tfvarsPath)
+	if err != nil {
+		return err
+	}
+
+	datastore := config.Datastore
+	if datastore == "" {
+		datastore = vars["datastore_type"]
+	}
+	if datastore != "sqlite" || vars["no_of_server_nodes"] == "" {
+		return nil
+	}
+
+	servers, err := strconv.Atoi(vars["no_of_server_nodes"])
+	if err != nil {
+		return fmt.Errorf("invalid no_of_server_nodes: %q in %s", vars["no_of_server_nodes"], tfvarsPath)
+	}
+	if servers > 1 {
+		return fmt.Errorf("datastore sqlite supports a single server, got no_of_server_nodes = %d in %s",
+			servers, tfvarsPath)
+	}
+
+	return nil
+}
+
+// readTFVars returns the name = value pairs of the tfvars file, empty when the file does not exist.
+func readTFVars(path string) (map[string]string, error) {
+	vars := map[string]string{}
+
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return vars, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tfvars %s: %w", path, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			continue
+		}
+		vars[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"`)
+	}
+
+	return vars, scanner.Err()
+}
+
+// setEnv loads the legacy KEY=VALUE .env file into the process env.
 func setEnv(fullPath string) error {
 	file, err := os.Open(fullPath)
 	if err != nil {
-		fmt.Println("failed to open file:", err)
-		return err
+		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
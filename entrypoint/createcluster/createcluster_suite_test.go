@@ -8,6 +8,8 @@ import (
 	"github.com/rancher/distros-test-framework/config"
 	"github.com/rancher/distros-test-framework/factory"
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/diagnostics"
+	"github.com/rancher/distros-test-framework/pkg/report"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -23,11 +25,19 @@ func TestMain(m *testing.M) {
 
 	configPath, err := shared.EnvDir("entrypoint")
 	if err != nil {
-		return
+		shared.LogLevel("error", "error getting config path: %v\n", err)
+		os.Exit(1)
 	}
+
 	cfg, err = config.AddConfigEnv(configPath)
 	if err != nil {
-		return
+		shared.LogLevel("error", "error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err = customflag.AddConfigDefaults(cfg); err != nil {
+		shared.LogLevel("error", "error applying config to flags: %v\n", err)
+		os.Exit(1)
 	}
 
 	os.Exit(m.Run())
@@ -40,9 +50,32 @@ func TestCreateClusterSuite(t *testing.T) {
 
 var _ = AfterSuite(func() {
 	g := GinkgoT()
+	report.AddVersion()
+	shared.CloseSSHConnections()
+
 	if customflag.ServiceFlag.ClusterConfig.Destroy {
 		status, err := factory.DestroyCluster(g)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(status).To(Equal("cluster destroyed"))
+		if cfg.Provisioner == "existing" {
+			Expect(status).To(Equal("cluster not destroyed, existing cluster"))
+		} else {
+			Expect(status).To(Equal("cluster destroyed"))
+		}
+	}
+})
+
+var _ = ReportAfterEach(func(r SpecReport) {
+	report.AddSpec(r)
+
+	if r.Failed() {
+		if _, err := diagnostics.Collect(r.FullText()); err != nil {
+			shared.LogLevel("error", "error collecting diagnostics: %v\n", err)
+		}
+	}
+})
+
+var _ = ReportAfterSuite("reports", func(r Report) {
+	if err := report.Write(r); err != nil {
+		shared.LogLevel("error", "error writing reports: %v\n", err)
 	}
 })
@@ -0,0 +1,70 @@
+package datastore
+
+import (
+	"fmt"
+
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/testcase"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+var _ = Describe("Test:", func() {
+
+	It("Start Up with no issues", func() {
+		testcase.TestBuildCluster(GinkgoT())
+	})
+
+	It("Validates the servers run on the datastore", func() {
+		testcase.TestDatastore()
+	})
+
+	It("Validate Nodes", func() {
+		testcase.TestNodeStatus(
+			assert.NodeAssertReadyStatus(),
+			nil,
+		)
+	})
+
+	It("Validate Pods", func() {
+		testcase.TestPodStatus(
+			assert.PodAssertRestart(),
+			assert.PodAssertReady(),
+			assert.PodAssertStatus(),
+		)
+	})
+
+	It("Verifies ClusterIP Service", func() {
+		testcase.TestServiceClusterIp(true)
+	})
+
+	It("Verifies NodePort Service", func() {
+		testcase.TestServiceNodePort(true)
+	})
+
+	It("Verifies Ingress", func() {
+		testcase.TestIngress(true)
+	})
+
+	It("Verifies Daemonset", func() {
+		testcase.TestDaemonset(true)
+	})
+
+	It("Verifies dns access", func() {
+		testcase.TestDnsAccess(true)
+	})
+
+	if cfg.Product == "k3s" {
+		It("Verifies Local Path Provisioner storage", func() {
+			testcase.TestLocalPathProvisionerStorage(true)
+		})
+	}
+})
+
+var _ = AfterEach(func() {
+	if CurrentSpecReport().Failed() {
+		fmt.Printf("\nFAILED! %s\n", CurrentSpecReport().FullText())
+	} else {
+		fmt.Printf("\nPASSED! %s\n", CurrentSpecReport().FullText())
+	}
+})
@@ -8,6 +8,7 @@ import (
 	"github.com/rancher/distros-test-framework/pkg/testcase"
 
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Test:", func() {
@@ -47,17 +48,25 @@ var _ = Describe("Test:", func() {
 		testcase.TestSelinuxContext()
 	})
 
-	if customflag.ServiceFlag.InstallMode.String() != "" {
-		It("Upgrade manual", func() {
-			_ = testcase.TestUpgradeClusterManually(customflag.ServiceFlag.InstallMode.String())
+	hops := customflag.ServiceFlag.InstallMode.Hops
+	for i, hop := range hops {
+		hop := hop
+		name := fmt.Sprintf("hop %d/%d to %s", i+1, len(hops), hop)
+
+		It("Upgrade manual "+name, func() {
+			err := testcase.TestUpgradeClusterManually(hop)
+			Expect(err).NotTo(HaveOccurred(), err)
 		})
 
-		It("Validate Nodes Post upgrade", func() {
+		It("Validate Nodes Post upgrade "+name, func() {
 			testcase.TestNodeStatus(
 				assert.NodeAssertReadyStatus(),
-				assert.NodeAssertVersionTypeUpgrade(customflag.ServiceFlag),
+				assert.NodeAssertUpgradedTo(hop),
 			)
 		})
+	}
+
+	if len(hops) > 0 {
 
 		It("Validate Pods Post upgrade", func() {
 			testcase.TestPodStatus(
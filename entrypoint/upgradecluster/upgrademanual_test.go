@@ -4,12 +4,15 @@ package upgradecluster
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rancher/distros-test-framework/pkg/assert"
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/report"
 	"github.com/rancher/distros-test-framework/pkg/testcase"
 
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("Test:", func() {
@@ -63,53 +66,63 @@ var _ = Describe("Test:", func() {
 		})
 	}
 
-	It("Upgrade Manual", func() {
-		_ = testcase.TestUpgradeClusterManually(customflag.ServiceFlag.InstallMode.String())
-	})
+	hops := customflag.ServiceFlag.InstallMode.Hops
+	report.AddProperty("upgrade_path", strings.Join(hops, " -> "))
 
-	It("Checks Node Status pos upgrade and validate version", func() {
-		testcase.TestNodeStatus(
-			assert.NodeAssertReadyStatus(),
-			assert.NodeAssertVersionTypeUpgrade(customflag.ServiceFlag),
-		)
-	})
+	for i, hop := range hops {
+		hop := hop
+		last := i == len(hops)-1
+		name := fmt.Sprintf("hop %d/%d to %s", i+1, len(hops), hop)
 
-	It("Checks Pod Status pos upgrade", func() {
-		testcase.TestPodStatus(
-			assert.PodAssertRestart(),
-			assert.PodAssertReady(),
-			assert.PodAssertStatus(),
-		)
-	})
+		It("Upgrade Manual "+name, func() {
+			err := testcase.TestUpgradeClusterManually(hop)
+			Expect(err).NotTo(HaveOccurred(), err)
+		})
 
-	It("Verifies ClusterIP Service after upgrade", func() {
-		testcase.TestServiceClusterIp(true)
-	})
+		It("Checks Node Status pos upgrade and validate version "+name, func() {
+			testcase.TestNodeStatus(
+				assert.NodeAssertReadyStatus(),
+				assert.NodeAssertUpgradedTo(hop),
+			)
+		})
 
-	It("Verifies NodePort Service after upgrade", func() {
-		testcase.TestServiceNodePort(true)
-	})
+		It("Checks Pod Status pos upgrade "+name, func() {
+			testcase.TestPodStatus(
+				assert.PodAssertRestart(),
+				assert.PodAssertReady(),
+				assert.PodAssertStatus(),
+			)
+		})
 
-	It("Verifies Ingress after upgrade", func() {
-		testcase.TestIngress(true)
-	})
+		It("Verifies ClusterIP Service after upgrade "+name, func() {
+			testcase.TestServiceClusterIp(last)
+		})
 
-	It("Verifies Daemonset after upgrade", func() {
-		testcase.TestDaemonset(true)
-	})
+		It("Verifies NodePort Service after upgrade "+name, func() {
+			testcase.TestServiceNodePort(last)
+		})
 
-	It("Verifies dns access after upgrade", func() {
-		testcase.TestDnsAccess(true)
-	})
+		It("Verifies Ingress after upgrade "+name, func() {
+			testcase.TestIngress(last)
+		})
 
-	if cfg.Product == "k3s" {
-		It("Verifies LoadBalancer Service after upgrade", func() {
-			testcase.TestServiceLoadBalancer(true)
+		It("Verifies Daemonset after upgrade "+name, func() {
+			testcase.TestDaemonset(last)
 		})
 
-		It("Verifies Local Path Provisioner storage after upgrade", func() {
-			testcase.TestLocalPathProvisionerStorage(true)
+		It("Verifies dns access after upgrade "+name, func() {
+			testcase.TestDnsAccess(last)
 		})
+
+		if cfg.Product == "k3s" {
+			It("Verifies LoadBalancer Service after upgrade "+name, func() {
+				testcase.TestServiceLoadBalancer(last)
+			})
+
+			It("Verifies Local Path Provisioner storage after upgrade "+name, func() {
+				testcase.TestLocalPathProvisionerStorage(last)
+			})
+		}
 	}
 })
 
@@ -0,0 +1,105 @@
+//go:build upgraderollback
+
+package upgradecluster
+
+import (
+	"fmt"
+
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/testcase"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func init() {
+	validateFlags = validateRollbackFlags
+}
+
+// validateRollbackFlags rejects the install modes rollback does not support,
+// it upgrades to a single version and reinstalls the version recorded before.
+func validateRollbackFlags() error {
+	mode := customflag.ServiceFlag.InstallMode
+	switch {
+	case len(mode.Hops) > 1:
+		return fmt.Errorf("rollback upgrades to a single version, got: %v", mode.Hops)
+	case mode.Commit != "":
+		return fmt.Errorf("rollback upgrades to a version, commit is not supported: %s", mode.Commit)
+	case mode.Version == "":
+		return fmt.Errorf("rollback requires -installVersionOrCommit with a version to upgrade to")
+	}
+
+	return nil
+}
+
+var _ = Describe("Test:", func() {
+
+	It("Start Up with no issues", func() {
+		testcase.TestBuildCluster(GinkgoT())
+	})
+
+	It("Validate Node", func() {
+		testcase.TestNodeStatus(
+			assert.NodeAssertReadyStatus(),
+			nil,
+		)
+	})
+
+	It("Validate Pod", func() {
+		testcase.TestPodStatus(
+			assert.PodAssertRestart(),
+			assert.PodAssertReady(),
+			assert.PodAssertStatus(),
+		)
+	})
+
+	It("Verifies ClusterIP Service", func() {
+		testcase.TestServiceClusterIp(false)
+	})
+
+	It("Verifies NodePort Service", func() {
+		testcase.TestServiceNodePort(false)
+	})
+
+	It("Verifies Ingress", func() {
+		testcase.TestIngress(false)
+	})
+
+	It("Verifies Daemonset", func() {
+		testcase.TestDaemonset(false)
+	})
+
+	It("Upgrade and Rollback to the previous version", func() {
+		err := testcase.TestUpgradeRollback(customflag.ServiceFlag.InstallMode.Version)
+		Expect(err).NotTo(HaveOccurred(), err)
+	})
+
+	It("Verifies ClusterIP Service after rollback", func() {
+		testcase.TestServiceClusterIp(true)
+	})
+
+	It("Verifies NodePort Service after rollback", func() {
+		testcase.TestServiceNodePort(true)
+	})
+
+	It("Verifies Ingress after rollback", func() {
+		testcase.TestIngress(true)
+	})
+
+	It("Verifies Daemonset after rollback", func() {
+		testcase.TestDaemonset(true)
+	})
+
+	It("Verifies dns access after rollback", func() {
+		testcase.TestDnsAccess(true)
+	})
+})
+
+var _ = AfterEach(func() {
+	if CurrentSpecReport().Failed() {
+		fmt.Printf("\nFAILED! %s\n", CurrentSpecReport().FullText())
+	} else {
+		fmt.Printf("\nPASSED! %s\n", CurrentSpecReport().FullText())
+	}
+})
@@ -4,12 +4,15 @@ package upgradecluster
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/rancher/distros-test-framework/pkg/assert"
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/report"
 	"github.com/rancher/distros-test-framework/pkg/testcase"
 
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("SUC Upgrade Tests:", func() {
@@ -53,44 +56,54 @@ var _ = Describe("SUC Upgrade Tests:", func() {
 		testcase.TestDnsAccess(false)
 	})
 
-	It("\nUpgrade via SUC", func() {
-		_ = testcase.TestUpgradeClusterSUC(customflag.ServiceFlag.SUCUpgradeVersion.String())
-	})
-
-	It("Checks Node Status post-upgrade", func() {
-		testcase.TestNodeStatus(
-			assert.NodeAssertReadyStatus(),
-			assert.NodeAssertVersionUpgraded(),
-		)
-	})
-
-	It("Checks Pod Status post-upgrade", func() {
-		testcase.TestPodStatus(
-			nil,
-			assert.PodAssertReady(),
-			assert.PodAssertStatus(),
-		)
-	})
-
-	It("Verifies ClusterIP Service post-upgrade", func() {
-		testcase.TestServiceClusterIp(true)
-	})
-
-	It("Verifies NodePort Service post-upgrade", func() {
-		testcase.TestServiceNodePort(true)
-	})
-
-	It("Verifies Ingress post-upgrade", func() {
-		testcase.TestIngress(true)
-	})
-
-	It("Verifies Daemonset post-upgrade", func() {
-		testcase.TestDaemonset(true)
-	})
-
-	It("Verifies DNS Access post-upgrade", func() {
-		testcase.TestDnsAccess(true)
-	})
+	hops := customflag.ServiceFlag.SUCUpgradeVersion.Hops
+	report.AddProperty("upgrade_path", strings.Join(hops, " -> "))
+
+	for i, hop := range hops {
+		hop := hop
+		last := i == len(hops)-1
+		name := fmt.Sprintf("hop %d/%d to %s", i+1, len(hops), hop)
+
+		It("\nUpgrade via SUC "+name, func() {
+			err := testcase.TestUpgradeClusterSUC(hop)
+			Expect(err).NotTo(HaveOccurred(), err)
+		})
+
+		It("Checks Node Status post-upgrade "+name, func() {
+			testcase.TestNodeStatus(
+				assert.NodeAssertReadyStatus(),
+				assert.NodeAssertUpgradedTo(hop),
+			)
+		})
+
+		It("Checks Pod Status post-upgrade "+name, func() {
+			testcase.TestPodStatus(
+				nil,
+				assert.PodAssertReady(),
+				assert.PodAssertStatus(),
+			)
+		})
+
+		It("Verifies ClusterIP Service post-upgrade "+name, func() {
+			testcase.TestServiceClusterIp(last)
+		})
+
+		It("Verifies NodePort Service post-upgrade "+name, func() {
+			testcase.TestServiceNodePort(last)
+		})
+
+		It("Verifies Ingress post-upgrade "+name, func() {
+			testcase.TestIngress(last)
+		})
+
+		It("Verifies Daemonset post-upgrade "+name, func() {
+			testcase.TestDaemonset(last)
+		})
+
+		It("Verifies DNS Access post-upgrade "+name, func() {
+			testcase.TestDnsAccess(last)
+		})
+	}
 })
 
 var _ = AfterEach(func() {
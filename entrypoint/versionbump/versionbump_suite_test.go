@@ -4,11 +4,15 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/rancher/distros-test-framework/config"
 	"github.com/rancher/distros-test-framework/factory"
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/diagnostics"
+	"github.com/rancher/distros-test-framework/pkg/report"
 	"github.com/rancher/distros-test-framework/pkg/template"
 	"github.com/rancher/distros-test-framework/shared"
 
@@ -16,21 +20,51 @@ import (
 	. "github.com/onsi/gomega"
 )
 
-var cfg *config.ProductConfig
+var (
+	cfg  *config.ProductConfig
+	plan *template.Plan
+)
 
 func TestMain(m *testing.M) {
 	flag.StringVar(&template.TestMapTemplate.Cmd, "cmd", "", "Comma separated list of commands to execute")
 	flag.StringVar(&template.TestMapTemplate.ExpectedValue, "expectedValue", "", "Comma separated list of expected values for commands")
 	flag.StringVar(&template.TestMapTemplate.ExpectedValueUpgrade, "expectedValueUpgrade", "", "Expected value of the command ran after upgrading")
+	flag.StringVar(&template.TestMapTemplate.Target, "target", "",
+		"Nodes to run the commands on: all, servers, agents, etcd, control-plane, windows, host or label:<sel>")
 	flag.Var(&customflag.ServiceFlag.InstallMode, "installVersionOrCommit", "Upgrade with version or commit")
 	flag.Var(&customflag.ServiceFlag.Channel, "channel", "channel to use on install or upgrade")
 	flag.Var(&customflag.TestCaseNameFlag, "testCase", "Comma separated list of test case names to run")
+	flag.Var(&customflag.CertServicesFlag, "certServices",
+		"Comma separated list of services TestCertRotation rotates, all when empty")
 	flag.StringVar(&customflag.ServiceFlag.TestConfig.WorkloadName, "workloadName", "", "Name of the workload to a standalone deploy")
 	flag.BoolVar(&customflag.ServiceFlag.TestConfig.DeployWorkload, "deployWorkload", false, "Deploy workload customflag for tests passed in")
 	flag.Var(&customflag.ServiceFlag.ClusterConfig.Destroy, "destroy", "Destroy cluster after test")
 	flag.StringVar(&customflag.ServiceFlag.TestConfig.Description, "description", "", "Description of the test")
+	flag.StringVar(&customflag.ServiceFlag.TestConfig.Plan, "plan", "", "Name or path of the yaml plan to run")
 	flag.Parse()
 
+	configPath, err := shared.EnvDir("entrypoint")
+	if err != nil {
+		shared.LogLevel("error", "error getting config path: %v\n", err)
+		os.Exit(1)
+	}
+
+	cfg, err = config.AddConfigEnv(configPath)
+	if err != nil {
+		shared.LogLevel("error", "error loading config: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err = customflag.AddConfigDefaults(cfg); err != nil {
+		shared.LogLevel("error", "error applying config to flags: %v\n", err)
+		os.Exit(1)
+	}
+
+	if hops := customflag.ServiceFlag.InstallMode.Hops; len(hops) > 1 {
+		shared.LogLevel("error", "version bump upgrades to a single version or commit, got: %v\n", hops)
+		os.Exit(1)
+	}
+
 	customflag.ServiceFlag.TestConfig.TestFuncNames = customflag.TestCaseNameFlag
 	testFuncs, err := template.AddTestCases(customflag.ServiceFlag.TestConfig.TestFuncNames)
 	if err != nil {
@@ -46,17 +80,16 @@ func TestMain(m *testing.M) {
 		customflag.ServiceFlag.TestConfig.TestFuncs = testCaseFlags
 	}
 
-	configPath, err := shared.EnvDir("entrypoint")
-	if err != nil {
-		return
-	}
-
-	cfg, err = config.AddConfigEnv(configPath)
-	if err != nil {
-		return
+	if customflag.ServiceFlag.TestConfig.Plan != "" {
+		plan, err = template.LoadPlan(planPath(customflag.ServiceFlag.TestConfig.Plan))
+		if err != nil {
+			shared.LogLevel("error", "error loading plan: %v\n", err)
+			os.Exit(1)
+		}
 	}
 
-	if customflag.ServiceFlag.InstallMode.String() != "" && template.TestMapTemplate.ExpectedValueUpgrade == "" {
+	if customflag.ServiceFlag.TestConfig.Plan == "" &&
+		customflag.ServiceFlag.InstallMode.String() != "" && template.TestMapTemplate.ExpectedValueUpgrade == "" {
 		shared.LogLevel("error", "if you are using upgrade, please provide the expected value after upgrade")
 		os.Exit(1)
 	}
@@ -72,9 +105,41 @@ func TestVersionTestSuite(t *testing.T) {
 
 var _ = AfterSuite(func() {
 	g := GinkgoT()
+	report.AddVersion()
+	shared.CloseSSHConnections()
+
 	if customflag.ServiceFlag.ClusterConfig.Destroy {
 		status, err := factory.DestroyCluster(g)
 		Expect(err).NotTo(HaveOccurred())
-		Expect(status).To(Equal("cluster destroyed"))
+		if cfg.Provisioner == "existing" {
+			Expect(status).To(Equal("cluster not destroyed, existing cluster"))
+		} else {
+			Expect(status).To(Equal("cluster destroyed"))
+		}
+	}
+})
+
+var _ = ReportAfterEach(func(r SpecReport) {
+	report.AddSpec(r)
+
+	if r.Failed() {
+		if _, err := diagnostics.Collect(r.FullText()); err != nil {
+			shared.LogLevel("error", "error collecting diagnostics: %v\n", err)
+		}
+	}
+})
+
+var _ = ReportAfterSuite("reports", func(r Report) {
+	if err := report.Write(r); err != nil {
+		shared.LogLevel("error", "error writing reports: %v\n", err)
 	}
 })
+
+// planPath returns the plan file path from a plan name under plans directory or a file path.
+func planPath(plan string) string {
+	if strings.HasSuffix(plan, ".yaml") || strings.HasSuffix(plan, ".yml") {
+		return plan
+	}
+
+	return filepath.Join("plans", plan+".yaml")
+}
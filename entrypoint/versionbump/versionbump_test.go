@@ -11,6 +11,7 @@ import (
 	"github.com/rancher/distros-test-framework/pkg/testcase"
 
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 )
 
 var _ = Describe("VersionTemplate Upgrade:", func() {
@@ -33,11 +34,21 @@ var _ = Describe("VersionTemplate Upgrade:", func() {
 	})
 
 	It("Test Bump version", func() {
+		if plan != nil {
+			test, err := plan.Template(cfg.Product)
+			Expect(err).NotTo(HaveOccurred(), "error building plan template: %v", err)
+
+			template.VersionTemplate(*test)
+
+			return
+		}
+
 		template.VersionTemplate(template.VersionTestTemplate{
 			TestCombination: &template.RunCmd{
 				Run: []template.TestMap{
 					{
 						Cmd:                  template.TestMapTemplate.Cmd,
+						Target:               template.TestMapTemplate.Target,
 						ExpectedValue:        template.TestMapTemplate.ExpectedValue,
 						ExpectedValueUpgrade: template.TestMapTemplate.ExpectedValueUpgrade,
 					},
@@ -52,6 +63,18 @@ var _ = Describe("VersionTemplate Upgrade:", func() {
 			Description: customflag.ServiceFlag.TestConfig.Description,
 		})
 	})
+
+	if plan != nil {
+		for _, name := range plan.SpecTestCases() {
+			name := name
+			It("Verifies "+name+" after bump", func() {
+				testCases, err := template.AddTestCases([]string{name})
+				Expect(err).NotTo(HaveOccurred(), err)
+
+				testCases[0](plan.Workload())
+			})
+		}
+	}
 })
 
 var _ = AfterEach(func() {
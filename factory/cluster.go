@@ -1,17 +1,11 @@
 package factory
 
 import (
-	"fmt"
-	"path/filepath"
-	"strconv"
-
-	"github.com/gruntwork-io/terratest/modules/terraform"
 	. "github.com/onsi/ginkgo/v2"
-	"github.com/rancher/distros-test-framework/config"
 	"github.com/rancher/distros-test-framework/shared"
 )
 
-// AddCluster returns a singleton cluster with all terraform config and vars
+// AddCluster returns a singleton cluster created by the configured provisioner
 func AddCluster(g GinkgoTInterface) *Cluster {
 	once.Do(func() {
 		var err error
@@ -25,83 +19,40 @@ func AddCluster(g GinkgoTInterface) *Cluster {
 	return cluster
 }
 
-// newCluster creates a new cluster and returns his values from terraform config and vars
+// newCluster creates a new cluster and returns his values from the configured provisioner
 func newCluster(g GinkgoTInterface) (*Cluster, error) {
-	terraformOptions, varDir, err := addTerraformOptions()
+	p, err := newProvisioner()
 	if err != nil {
 		return nil, err
 	}
 
-	numServers, err := strconv.Atoi(terraform.GetVariableAsStringFromVarFile(
-		g,
-		varDir,
-		"no_of_server_nodes",
-	))
-	if err != nil {
-		return nil, shared.ReturnLogError(
-			"error getting no_of_server_nodes from var file: %w", err)
-	}
-
-	numAgents, err := strconv.Atoi(terraform.GetVariableAsStringFromVarFile(
-		g,
-		varDir,
-		"no_of_worker_nodes",
-	))
+	c, err := p.Create(g)
 	if err != nil {
-		return nil, shared.ReturnLogError(
-			"error getting no_of_worker_nodes from var file: %w\n", err)
+		return nil, err
 	}
 
-	fmt.Println("Creating Cluster")
-	terraform.InitAndApply(g, terraformOptions)
-
-	numServers, err = addSplitRole(g, varDir, numServers)
-	if err != nil {
+	if err = addSSHConfig(); err != nil {
 		return nil, err
 	}
 
-	c, err := addClusterConfig(g, varDir, terraformOptions)
-	if err != nil {
+	if err = addKnownHosts(c); err != nil {
 		return nil, err
 	}
 
-	c.NumServers = numServers
-	c.NumAgents = numAgents
-	c.Status = "cluster created"
-
 	return c, nil
 }
 
 // DestroyCluster destroys the cluster and returns it
 func DestroyCluster(g GinkgoTInterface) (string, error) {
-	var varDir string
-	configPath, err := shared.EnvDir("factory")
+	p, err := newProvisioner()
 	if err != nil {
-		return "", shared.ReturnLogError("error getting config path: %w", err)
+		return "", shared.ReturnLogError("error getting provisioner: %w", err)
 	}
 
-	cfg, err := config.AddConfigEnv(configPath)
-	if err != nil {
-		return "", shared.ReturnLogError("error getting config: %w", err)
-	}
-
-	varDir, err = filepath.Abs(shared.BasePath() +
-		fmt.Sprintf("/distros-test-framework/config/%s.tfvars", cfg.Product))
-	if err != nil {
-		return "", shared.ReturnLogError("invalid product: %s\n", cfg.Product)
-	}
-
-	tfDir, err := filepath.Abs(shared.BasePath() +
-		fmt.Sprintf("/distros-test-framework/modules/%s", cfg.Product))
-	if err != nil {
-		return "", shared.ReturnLogError("no module found for product: %s\n", cfg.Product)
-	}
-
-	terraformOptions := terraform.Options{
-		TerraformDir: tfDir,
-		VarFiles:     []string{varDir},
-	}
-	terraform.Destroy(g, &terraformOptions)
+	return p.Destroy(g)
+}
 
-	return "cluster destroyed", nil
+// GetCluster returns the cluster already added or nil, it never creates one
+func GetCluster() *Cluster {
+	return cluster
 }
@@ -38,6 +38,18 @@ type clusterConfig struct {
 	Arch             string
 }
 
+// Backend returns the datastore the servers run on, etcd, sqlite or the external db type.
+func (c clusterConfig) Backend() string {
+	if c.DataStore != "" {
+		return c.DataStore
+	}
+	if c.ExternalDb != "" {
+		return c.ExternalDb
+	}
+
+	return "etcd"
+}
+
 func loadConfig() (*config.ProductConfig, error) {
 	cfgPath, err := shared.EnvDir("factory")
 	if err != nil {
@@ -52,6 +64,44 @@ func loadConfig() (*config.ProductConfig, error) {
 	return cfg, nil
 }
 
+// addSSHConfig overrides the ssh user and key with the values from config when set.
+func addSSHConfig() error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return shared.ReturnLogError("error loading config: %w", err)
+	}
+
+	if cfg.SSH.User != "" {
+		shared.AwsUser = cfg.SSH.User
+	}
+	if cfg.SSH.Key != "" {
+		shared.AccessKey = cfg.SSH.Key
+	}
+
+	return nil
+}
+
+// addKnownHosts captures the linux nodes host keys into the known hosts file from config when set,
+// so the next ssh connections are pinned to them.
+func addKnownHosts(c *Cluster) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return shared.ReturnLogError("error loading config: %w", err)
+	}
+
+	if cfg.SSH.KnownHosts == "" {
+		return nil
+	}
+
+	ips := append(append([]string{}, c.ServerIPs...), c.AgentIPs...)
+	if err = shared.CaptureHostKeys(cfg.SSH.KnownHosts, ips...); err != nil {
+		return shared.ReturnLogError("error capturing host keys: %w", err)
+	}
+	shared.KnownHostsFile = cfg.SSH.KnownHosts
+
+	return nil
+}
+
 func addTerraformOptions() (*terraform.Options, string, error) {
 	cfg, err := loadConfig()
 	if err != nil {
@@ -62,9 +112,9 @@ func addTerraformOptions() (*terraform.Options, string, error) {
 	var tfDir string
 
 	varDir, err = filepath.Abs(shared.BasePath() +
-		fmt.Sprintf("/distros-test-framework/config/%s.tfvars", cfg.Product))
+		"/distros-test-framework/config/" + cfg.TFVars)
 	if err != nil {
-		return nil, "", shared.ReturnLogError("invalid product: %s\n", cfg.Product)
+		return nil, "", shared.ReturnLogError("invalid tfvars: %s\n", cfg.TFVars)
 	}
 
 	tfDir, err = filepath.Abs(shared.BasePath() +
@@ -76,11 +126,35 @@ func addTerraformOptions() (*terraform.Options, string, error) {
 	terraformOptions := &terraform.Options{
 		TerraformDir: tfDir,
 		VarFiles:     []string{varDir},
+		Vars:         datastoreVars(cfg.Product, cfg.Datastore),
 	}
 
 	return terraformOptions, varDir, nil
 }
 
+// datastoreVars returns the k3s datastore variables for the datastore set in config,
+// which take precedence over the var file.
+func datastoreVars(product, datastore string) map[string]interface{} {
+	if product != "k3s" || datastore == "" {
+		return nil
+	}
+
+	if datastore == "etcd" || datastore == "sqlite" {
+		return map[string]interface{}{"datastore_type": datastore, "external_db": ""}
+	}
+
+	return map[string]interface{}{"datastore_type": "", "external_db": datastore}
+}
+
+// tfVariable returns the variable passed in the terraform options or else from the var file.
+func tfVariable(g GinkgoTInterface, terraformOptions *terraform.Options, varDir, name string) string {
+	if value, ok := terraformOptions.Vars[name]; ok {
+		return fmt.Sprint(value)
+	}
+
+	return terraform.GetVariableAsStringFromVarFile(g, varDir, name)
+}
+
 func addClusterConfig(
 	g GinkgoTInterface,
 	varDir string,
@@ -101,9 +175,9 @@ func addClusterConfig(
 	c.ServerIPs = strings.Split(terraform.Output(g, terraformOptions, "master_ips"), ",")
 
 	if cfg.Product == "k3s" {
-		c.Config.DataStore = terraform.GetVariableAsStringFromVarFile(g, varDir, "datastore_type")
+		c.Config.DataStore = tfVariable(g, terraformOptions, varDir, "datastore_type")
 		if c.Config.DataStore == "" {
-			c.Config.ExternalDb = terraform.GetVariableAsStringFromVarFile(g, varDir, "external_db")
+			c.Config.ExternalDb = tfVariable(g, terraformOptions, varDir, "external_db")
 			c.Config.RenderedTemplate = terraform.Output(g, terraformOptions, "rendered_template")
 		}
 	}
@@ -0,0 +1,108 @@
+package factory
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rancher/distros-test-framework/shared"
+	"gopkg.in/yaml.v3"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// existingProvisioner describes an already running cluster from a kubeconfig and an inventory file.
+type existingProvisioner struct {
+	product       string
+	kubeConfig    string
+	inventoryPath string
+}
+
+// inventory represents the nodes and settings of an existing cluster.
+type inventory struct {
+	Arch          string   `yaml:"arch"`
+	SSHUser       string   `yaml:"ssh_user"`
+	SSHKey        string   `yaml:"ssh_key"`
+	DataStore     string   `yaml:"datastore_type"`
+	ExternalDb    string   `yaml:"external_db"`
+	Servers       []string `yaml:"servers"`
+	Agents        []string `yaml:"agents"`
+	WindowsAgents []string `yaml:"windows_agents"`
+}
+
+// Create does not provision anything and returns the existing cluster.
+func (e *existingProvisioner) Create(g GinkgoTInterface) (*Cluster, error) {
+	fmt.Println("Using existing cluster from inventory:", e.inventoryPath)
+
+	c, err := e.Describe(g)
+	if err != nil {
+		return nil, err
+	}
+	c.Status = "cluster created"
+
+	return c, nil
+}
+
+// Describe returns the cluster built from the kubeconfig and inventory file.
+func (e *existingProvisioner) Describe(_ GinkgoTInterface) (*Cluster, error) {
+	if e.kubeConfig == "" || e.inventoryPath == "" {
+		return nil, shared.ReturnLogError("kubeconfig and inventory are required for existing cluster")
+	}
+
+	if _, err := os.Stat(e.kubeConfig); err != nil {
+		return nil, shared.ReturnLogError("error reading kubeconfig: %w\n", err)
+	}
+
+	inv, err := loadInventory(e.inventoryPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(inv.Servers) == 0 {
+		return nil, shared.ReturnLogError("inventory must have at least one server")
+	}
+
+	shared.KubeConfigFile = e.kubeConfig
+	shared.AwsUser = inv.SSHUser
+	shared.AccessKey = inv.SSHKey
+	shared.Arch = inv.Arch
+	if shared.Arch == "" {
+		shared.Arch = "amd64"
+	}
+
+	c := &Cluster{
+		ServerIPs:    inv.Servers,
+		AgentIPs:     inv.Agents,
+		WinAgentIPs:  inv.WindowsAgents,
+		NumServers:   len(inv.Servers),
+		NumAgents:    len(inv.Agents),
+		NumWinAgents: len(inv.WindowsAgents),
+	}
+	c.Config.Arch = shared.Arch
+	c.Config.Product = e.product
+	c.Config.DataStore = inv.DataStore
+	c.Config.ExternalDb = inv.ExternalDb
+
+	return c, nil
+}
+
+// Destroy is a no-op since the cluster lifecycle is not owned by the framework.
+func (e *existingProvisioner) Destroy(_ GinkgoTInterface) (string, error) {
+	fmt.Println("Skipping destroy for existing cluster")
+
+	return "cluster not destroyed, existing cluster", nil
+}
+
+// loadInventory reads the inventory file for the existing cluster.
+func loadInventory(path string) (*inventory, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, shared.ReturnLogError("error reading inventory file: %w\n", err)
+	}
+
+	inv := &inventory{}
+	if err = yaml.Unmarshal(content, inv); err != nil {
+		return nil, shared.ReturnLogError("error parsing inventory file: %w\n", err)
+	}
+
+	return inv, nil
+}
@@ -0,0 +1,41 @@
+package factory
+
+import (
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// Provisioner is the backend responsible for the cluster lifecycle.
+//
+// Create provisions a new cluster and returns it.
+//
+// Describe returns an already provisioned cluster without changing it.
+//
+// Destroy tears down the cluster and returns its status.
+type Provisioner interface {
+	Create(g GinkgoTInterface) (*Cluster, error)
+	Describe(g GinkgoTInterface) (*Cluster, error)
+	Destroy(g GinkgoTInterface) (string, error)
+}
+
+// newProvisioner returns the provisioner selected on config, terraform is used as default.
+func newProvisioner() (Provisioner, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, shared.ReturnLogError("error loading config: %w", err)
+	}
+
+	switch cfg.Provisioner {
+	case "", "terraform":
+		return &terraformProvisioner{product: cfg.Product}, nil
+	case "existing":
+		return &existingProvisioner{
+			product:       cfg.Product,
+			kubeConfig:    cfg.KubeConfig,
+			inventoryPath: cfg.Inventory,
+		}, nil
+	default:
+		return nil, shared.ReturnLogError("unknown provisioner: %s\n", cfg.Provisioner)
+	}
+}
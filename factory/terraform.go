@@ -0,0 +1,89 @@
+package factory
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// terraformProvisioner provisions the cluster on AWS using the terraform modules for the product.
+type terraformProvisioner struct {
+	product string
+}
+
+// Create applies the terraform modules and returns the cluster created.
+func (t *terraformProvisioner) Create(g GinkgoTInterface) (*Cluster, error) {
+	terraformOptions, _, err := addTerraformOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	fmt.Println("Creating Cluster")
+	terraform.InitAndApply(g, terraformOptions)
+
+	c, err := t.Describe(g)
+	if err != nil {
+		return nil, err
+	}
+	c.Status = "cluster created"
+
+	return c, nil
+}
+
+// Describe returns the cluster from terraform outputs and var file without applying.
+func (t *terraformProvisioner) Describe(g GinkgoTInterface) (*Cluster, error) {
+	terraformOptions, varDir, err := addTerraformOptions()
+	if err != nil {
+		return nil, err
+	}
+
+	numServers, err := strconv.Atoi(terraform.GetVariableAsStringFromVarFile(
+		g,
+		varDir,
+		"no_of_server_nodes",
+	))
+	if err != nil {
+		return nil, shared.ReturnLogError(
+			"error getting no_of_server_nodes from var file: %w", err)
+	}
+
+	numAgents, err := strconv.Atoi(terraform.GetVariableAsStringFromVarFile(
+		g,
+		varDir,
+		"no_of_worker_nodes",
+	))
+	if err != nil {
+		return nil, shared.ReturnLogError(
+			"error getting no_of_worker_nodes from var file: %w\n", err)
+	}
+
+	numServers, err = addSplitRole(g, varDir, numServers)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := addClusterConfig(g, varDir, terraformOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	c.NumServers = numServers
+	c.NumAgents = numAgents
+
+	return c, nil
+}
+
+// Destroy destroys the terraform resources for the product.
+func (t *terraformProvisioner) Destroy(g GinkgoTInterface) (string, error) {
+	terraformOptions, _, err := addTerraformOptions()
+	if err != nil {
+		return "", err
+	}
+	terraform.Destroy(g, terraformOptions)
+
+	return "cluster destroyed", nil
+}
@@ -64,6 +64,32 @@ if  [[ "$node_os" = *"rhel"* ]] || [[ "$node_os" = *"centos"* ]]
 fi
 }
 
+airgap_dir=/tmp/distros-airgap
+install_url=https://get.k3s.io
+
+# stage_airgap installs from the artifacts terraform copied from the airgap_dir tfvar, when set,
+# placing the images and the k3s binary of the node arch where k3s picks them up without downloading.
+stage_airgap() {
+  if [ ! -f "$airgap_dir/install.sh" ]
+  then
+    return
+  fi
+
+  local arch binary
+  arch=$(uname -m | sed -e 's/x86_64/amd64/' -e 's/aarch64/arm64/')
+  binary=k3s
+  if [ "$arch" != "amd64" ]
+  then
+    binary="k3s-$arch"
+  fi
+
+  mkdir -p /var/lib/rancher/k3s/agent/images
+  cp "$airgap_dir"/*images*-"$arch".* /var/lib/rancher/k3s/agent/images/
+  install -m 755 "$airgap_dir/$binary" /usr/local/bin/k3s
+  export INSTALL_K3S_SKIP_DOWNLOAD=true
+  install_url="file://$airgap_dir/install.sh"
+}
+
 export "${2}"="${3}"
 
 install(){
@@ -77,13 +103,13 @@ install(){
 
 if [[ "$version" == *"v1.18"* ]] || [[ "$version" == *"v1.17"* ]] && [[ -n "$worker_flags" ]]
   then
-    curl -sfL https://get.k3s.io | sh -s - "$install_mode" --node-external-ip="$ip" --server https://"$server_ip":6443 --token "$token"
+    curl -sfL "$install_url" | sh -s - "$install_mode" --node-external-ip="$ip" --server https://"$server_ip":6443 --token "$token"
 else
     if [[ -n "$channel"  ]]
     then
-      curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL=$channel sh -s - agent --node-external-ip="$ip"
+      curl -sfL "$install_url" | INSTALL_K3S_CHANNEL=$channel sh -s - agent --node-external-ip="$ip"
     else
-      curl -sfL https://get.k3s.io | sh -s - agent --node-external-ip="$ip"
+      curl -sfL "$install_url" | sh -s - agent --node-external-ip="$ip"
     fi
     sleep 10
 fi
@@ -94,6 +120,7 @@ main() {
   add_config "$7"
   rhel "$1" "$8" "$9"
   disable_cloud_setup "$1"
+  stage_airgap
   install "$3" "$7" "$2" "$6" "$4" "$5" "${10}"
 }
 main "$@"
\ No newline at end of file
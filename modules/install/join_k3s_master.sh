@@ -15,6 +15,7 @@ create_config() {
   local server_ip="${2}"
   local token="${3}"
   local node_external_ip="${4}"
+  local datastore_type="${5}"
   cat <<EOF >>/etc/rancher/k3s/config.yaml
 write-kubeconfig-mode: "0644"
 tls-san:
@@ -22,8 +23,11 @@ tls-san:
 server: https://${server_ip}:6443
 token: "${token}"
 node-external-ip: "${node_external_ip}"
-cluster-init: true
 EOF
+  if [ "$datastore_type" = "etcd" ]
+  then
+    echo "cluster-init: true" >> /etc/rancher/k3s/config.yaml
+  fi
 }
 
 add_config() {
@@ -84,6 +88,32 @@ policy_files() {
   sleep 20
 }
 
+airgap_dir=/tmp/distros-airgap
+install_url=https://get.k3s.io
+
+# stage_airgap installs from the artifacts terraform copied from the airgap_dir tfvar, when set,
+# placing the images and the k3s binary of the node arch where k3s picks them up without downloading.
+stage_airgap() {
+  if [ ! -f "$airgap_dir/install.sh" ]
+  then
+    return
+  fi
+
+  local arch binary
+  arch=$(uname -m | sed -e 's/x86_64/amd64/' -e 's/aarch64/arm64/')
+  binary=k3s
+  if [ "$arch" != "amd64" ]
+  then
+    binary="k3s-$arch"
+  fi
+
+  mkdir -p /var/lib/rancher/k3s/agent/images
+  cp "$airgap_dir"/*images*-"$arch".* /var/lib/rancher/k3s/agent/images/
+  install -m 755 "$airgap_dir/$binary" /usr/local/bin/k3s
+  export INSTALL_K3S_SKIP_DOWNLOAD=true
+  install_url="file://$airgap_dir/install.sh"
+}
+
 export "${3}"="${4}"
 
 install() {
@@ -92,26 +122,26 @@ install() {
     local channel="${3}"
     local datastore_endpoint="${4}"
 
-    if [ "$datastore_type" = "etcd" ]; then
+    if [ "$datastore_type" = "etcd" ] || [ "$datastore_type" = "sqlite" ]; then
         if [[ "$version" == *"v1.18"* ]] || [[ "$version" == *"v1.17"* ]]
          then
-            curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -
+            curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -
         else
             if [[ -n "$channel" ]]; then
-                curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -
+                curl -sfL "$install_url" | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -
             else
-                curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -
+                curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -
             fi
         fi
     else
         if [[ "$version" == *"v1.18"* ]] || [[ "$version" == *"v1.17"* ]]
           then
-            curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -s - server --datastore-endpoint="$datastore_endpoint"
+            curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -s - server --datastore-endpoint="$datastore_endpoint"
         else
             if [[ -n "$channel" ]]; then
-                curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -s - server --datastore-endpoint="$datastore_endpoint"
+                curl -sfL "$install_url" | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -s - server --datastore-endpoint="$datastore_endpoint"
             else
-                curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -s - server  --datastore-endpoint="$datastore_endpoint"
+                curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -s - server  --datastore-endpoint="$datastore_endpoint"
             fi
         fi
     fi
@@ -120,11 +150,12 @@ export alias k=kubectl
 main() {
 
   create_directories
-  create_config "$2" "$7" "$8" "$6"
+  create_config "$2" "$7" "$8" "$6" "$5"
   add_config  "${10}"
   policy_files "${10}" "$4"
   rhel "$1" "${11}" "${12}"
   disable_cloud_setup "$1"
+  stage_airgap
   install "$5" "$4" "${13}" "$9"
 }
 main "$@"
\ No newline at end of file
@@ -77,11 +77,23 @@ then
   export INSTALL_RKE2_METHOD="$install_method"
 fi
 
+airgap_dir=/tmp/distros-airgap
+install_url=https://get.rke2.io
+# airgap_dir tfvar set, install from the artifacts terraform copied instead of downloading
+if [ -f "$airgap_dir/install.sh" ]
+then
+  arch=$(uname -m | sed -e 's/x86_64/amd64/' -e 's/aarch64/arm64/')
+  mkdir -p /var/lib/rancher/rke2/agent/images
+  cp "$airgap_dir"/*images*-"$arch".* /var/lib/rancher/rke2/agent/images/
+  export INSTALL_RKE2_ARTIFACT_PATH="$airgap_dir" INSTALL_RKE2_METHOD=tar
+  install_url="file://$airgap_dir/install.sh"
+fi
+
 if [ "$rke2_channel" != "null" ]
 then
-    curl -sfL https://get.rke2.io | INSTALL_RKE2_CHANNEL="$rke2_channel" sh -
+    curl -sfL "$install_url" | INSTALL_RKE2_CHANNEL="$rke2_channel" sh -
 else
-    curl -sfL https://get.rke2.io | sh -
+    curl -sfL "$install_url" | sh -
 fi
 sleep 10
 if [ -n "$server_flags" ] && [[ "$server_flags" == *"cis"* ]]
@@ -13,14 +13,18 @@ create_directories() {
 create_config() {
   local fake_fqdn="${1}"
   local node_external_ip="${2}"
+  local datastore_type="${3}"
 
   cat <<EOF >>/etc/rancher/k3s/config.yaml
 write-kubeconfig-mode: "0644"
 tls-san:
   - ${fake_fqdn}
 node-external-ip: "${node_external_ip}"
-cluster-init: true
 EOF
+  if [ "$datastore_type" = "etcd" ]
+  then
+    echo "cluster-init: true" >> /etc/rancher/k3s/config.yaml
+  fi
 }
 
 add_config() {
@@ -82,6 +86,32 @@ fi
 
 }
 
+airgap_dir=/tmp/distros-airgap
+install_url=https://get.k3s.io
+
+# stage_airgap installs from the artifacts terraform copied from the airgap_dir tfvar, when set,
+# placing the images and the k3s binary of the node arch where k3s picks them up without downloading.
+stage_airgap() {
+  if [ ! -f "$airgap_dir/install.sh" ]
+  then
+    return
+  fi
+
+  local arch binary
+  arch=$(uname -m | sed -e 's/x86_64/amd64/' -e 's/aarch64/arm64/')
+  binary=k3s
+  if [ "$arch" != "amd64" ]
+  then
+    binary="k3s-$arch"
+  fi
+
+  mkdir -p /var/lib/rancher/k3s/agent/images
+  cp "$airgap_dir"/*images*-"$arch".* /var/lib/rancher/k3s/agent/images/
+  install -m 755 "$airgap_dir/$binary" /usr/local/bin/k3s
+  export INSTALL_K3S_SKIP_DOWNLOAD=true
+  install_url="file://$airgap_dir/install.sh"
+}
+
 export "${3}"="${4}"
 
 install() {
@@ -90,36 +120,75 @@ install() {
     local channel="${3}"
     local datastore_endpoint="${4}"
 
-  if [ "$datastore_type" = "etcd" ]
+  if [ "$datastore_type" = "etcd" ] || [ "$datastore_type" = "sqlite" ]
   then
-     echo "CLUSTER TYPE is ETCD and channel is $channel"
+     echo "CLUSTER TYPE is $datastore_type and channel is $channel"
      if [[ "$version" == *"v1.18"* ]] || [[ "$version" == *"v1.17"* ]]
      then
-         curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -s - server
+         curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -s - server
      else
          if [[ -n "$channel" ]]
          then
-             curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -s - server
+             curl -sfL "$install_url" | INSTALL_K3S_CHANNEL=$channel INSTALL_K3S_TYPE='server' sh -s - server
          else
-             curl -sfL https://get.k3s.io | INSTALL_K3S_TYPE='server' sh -s - server
+             curl -sfL "$install_url" | INSTALL_K3S_TYPE='server' sh -s - server
          fi
      fi
   else
     echo "CLUSTER TYPE is external db and channel is $channel"
     if [[ "$version" == *"v1.18"* ]] || [[ "$version" == *"v1.17"* ]]
     then
-        curl -sfL https://get.k3s.io | sh -s - server --datastore-endpoint="$datastore_endpoint"
+        curl -sfL "$install_url" | sh -s - server --datastore-endpoint="$datastore_endpoint"
     else
         if [[ -n "$channel" ]]
         then
-            curl -sfL https://get.k3s.io | INSTALL_K3S_CHANNEL=$channel sh -s - server --datastore-endpoint="$datastore_endpoint"
+            curl -sfL "$install_url" | INSTALL_K3S_CHANNEL=$channel sh -s - server --datastore-endpoint="$datastore_endpoint"
         else
-            curl -sfL https://get.k3s.io | sh -s - server  --datastore-endpoint="$datastore_endpoint"
+            curl -sfL "$install_url" | sh -s - server  --datastore-endpoint="$datastore_endpoint"
         fi
     fi
   fi
 }
 
+start_datastore_container() {
+  local datastore_endpoint="${1}"
+
+  if [[ "$datastore_endpoint" != *"127.0.0.1"* ]]
+  then
+    return
+  fi
+
+  echo "Starting datastore container for $datastore_endpoint"
+  curl -fsSL https://get.docker.com | sh
+  systemctl enable --now docker
+
+  local password
+  if [[ "$datastore_endpoint" == "postgres://"* ]]
+  then
+    password=$(echo "$datastore_endpoint" | sed -E 's|postgres://[^:]*:([^@]*)@.*|\1|')
+    docker run -d --name k3s-datastore --restart always -p 5432:5432 \
+      -e POSTGRES_PASSWORD="$password" -e POSTGRES_DB=k3s postgres:16
+    ready_cmd="docker exec k3s-datastore pg_isready -U postgres -d k3s"
+  else
+    password=$(echo "$datastore_endpoint" | sed -E 's|mysql://[^:]*:([^@]*)@.*|\1|')
+    docker run -d --name k3s-datastore --restart always -p 3306:3306 \
+      -e MYSQL_ROOT_PASSWORD="$password" -e MYSQL_DATABASE=k3s mysql:8.0
+    ready_cmd="docker exec k3s-datastore mysql -uroot -p$password -e 'select 1' k3s"
+  fi
+
+  timeElapsed=0
+  while (( timeElapsed < 300 )); do
+    if eval "$ready_cmd" >/dev/null 2>&1; then
+      return
+    fi
+    sleep 5
+    ((timeElapsed += 5))
+  done
+
+  echo "Timed out while waiting for the datastore container."
+  exit 1
+}
+
 wait_nodes() {
   export PATH="$PATH:/usr/local/bin"
   timeElapsed=0
@@ -199,11 +268,13 @@ config_files() {
 
 main() {
   create_directories
-  create_config "$2" "$6"
+  create_config "$2" "$6" "$5"
   add_config "$8"
   policy_files "$8" "$4"
   rhel "$1" "$9" "${10}"
   disable_cloud_setup "$1"
+  start_datastore_container "$7"
+  stage_airgap
   install "$5" "$4" "${11}" "$7"
   wait_nodes
   wait_ready_nodes
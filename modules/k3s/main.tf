@@ -31,6 +31,8 @@ module "master" {
    environment=var.environment
    create_lb=var.create_lb
    k3s_channel = var.k3s_channel
+   airgap_dir = var.airgap_dir
+   enable_ipv6 = var.enable_ipv6
 }
 module "worker" {
    source="./worker"
@@ -55,4 +57,6 @@ module "worker" {
    username=var.username
    password=var.password
    k3s_channel = var.k3s_channel
+   airgap_dir = var.airgap_dir
+   enable_ipv6 = var.enable_ipv6
 }
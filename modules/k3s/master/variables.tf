@@ -32,4 +32,6 @@ variable "k3s_channel" {}
 variable "create_lb" {
   description = "Create Network Load Balancer if set to true"
   type = bool
-}
\ No newline at end of file
+}
+variable "airgap_dir" {}
+variable "enable_ipv6" {}
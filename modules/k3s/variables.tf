@@ -30,6 +30,15 @@ variable "environment" {}
 variable "engine_mode" {}
 variable "install_mode" {}
 variable "k3s_channel" {}
+variable "enable_ipv6" {
+  description = "Give the nodes an IPv6 address for dual-stack or IPv6 clusters, the subnet needs an IPv6 cidr"
+  type        = bool
+  default     = false
+}
+variable "airgap_dir" {
+  description = "Local dir with the <version> dirs of airgap artifacts, when set the nodes install without downloading"
+  default     = ""
+}
 variable "create_lb" {
   description = "Create Network Load Balancer if set to true"
   type = bool
@@ -16,12 +16,20 @@ resource "aws_instance" "worker" {
     volume_type = "standard"
   }
   subnet_id              = var.subnets
+  ipv6_address_count     = var.enable_ipv6 ? 1 : 0
   availability_zone      = var.availability_zone
   vpc_security_group_ids = [var.sg_id]
   key_name               = var.key_name
   tags = {
     Name                 = "${var.resource_name}-worker"
   }
+  provisioner "remote-exec" {
+    inline = ["mkdir -p /tmp/distros-airgap"]
+  }
+  provisioner "file" {
+    source      = var.airgap_dir != "" ? "${var.airgap_dir}/${var.k3s_version}/" : "../install/airgap/"
+    destination = "/tmp/distros-airgap"
+  }
   provisioner "file" {
     source = "../install/join_k3s_agent.sh"
     destination = "/tmp/join_k3s_agent.sh"
@@ -22,3 +22,5 @@ variable "node_os" {}
 variable "install_mode" {}
 variable "key_name" {}
 variable "k3s_channel" {}
+variable "airgap_dir" {}
+variable "enable_ipv6" {}
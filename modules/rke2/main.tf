@@ -37,6 +37,8 @@ module "master" {
   install_mode   = var.install_mode
   install_method = var.install_method
   rke2_channel   = var.rke2_channel
+  airgap_dir     = var.airgap_dir
+  enable_ipv6    = var.enable_ipv6
   server_flags   = var.server_flags
   split_roles    = var.split_roles
   role_order     = var.role_order
@@ -73,6 +75,8 @@ module "worker" {
   install_mode   = var.install_mode
   install_method = var.install_method
   rke2_channel   = var.rke2_channel
+  airgap_dir     = var.airgap_dir
+  enable_ipv6    = var.enable_ipv6
   worker_flags   = var.worker_flags
 }
 
@@ -13,6 +13,7 @@ resource "aws_instance" "master" {
     volume_type = "standard"
   }
   subnet_id              = var.subnets
+  ipv6_address_count     = var.enable_ipv6 ? 1 : 0
   availability_zone      = var.availability_zone
   vpc_security_group_ids = [var.sg_id]
   key_name               = var.key_name
@@ -40,6 +41,13 @@ resource "aws_instance" "master" {
       "sudo /tmp/rke2_node_role.sh -1 \"${var.role_order}\" ${var.all_role_nodes} ${var.etcd_only_nodes} ${var.etcd_cp_nodes} ${var.etcd_worker_nodes} ${var.cp_only_nodes} ${var.cp_worker_nodes}",
     ]
   }
+  provisioner "remote-exec" {
+    inline = ["mkdir -p /tmp/distros-airgap"]
+  }
+  provisioner "file" {
+    source      = var.airgap_dir != "" ? "${var.airgap_dir}/${var.rke2_version}/" : "../install/airgap/"
+    destination = "/tmp/distros-airgap"
+  }
   provisioner "file" {
     source      = "../install/rke2_master.sh"
     destination = "/tmp/rke2_master.sh"
@@ -80,6 +88,7 @@ resource "aws_instance" "master2" {
     volume_type = "standard"
   }
   subnet_id              = var.subnets
+  ipv6_address_count     = var.enable_ipv6 ? 1 : 0
   availability_zone      = var.availability_zone
   vpc_security_group_ids = [var.sg_id]
   key_name               = var.key_name
@@ -108,6 +117,13 @@ resource "aws_instance" "master2" {
       "sudo /tmp/rke2_node_role.sh ${count.index} \"${var.role_order}\" ${var.all_role_nodes} ${var.etcd_only_nodes} ${var.etcd_cp_nodes} ${var.etcd_worker_nodes} ${var.cp_only_nodes} ${var.cp_worker_nodes}",
     ]
   }
+  provisioner "remote-exec" {
+    inline = ["mkdir -p /tmp/distros-airgap"]
+  }
+  provisioner "file" {
+    source      = var.airgap_dir != "" ? "${var.airgap_dir}/${var.rke2_version}/" : "../install/airgap/"
+    destination = "/tmp/distros-airgap"
+  }
   provisioner "file" {
     source      = "../install/join_rke2_master.sh"
     destination = "/tmp/join_rke2_master.sh"
@@ -43,4 +43,6 @@ variable "etcd_cp_nodes" {}
 variable "etcd_worker_nodes" {}
 variable "cp_only_nodes" {}
 variable "cp_worker_nodes" {}
-variable "optional_files" {}
\ No newline at end of file
+variable "optional_files" {}
+variable "airgap_dir" {}
+variable "enable_ipv6" {}
@@ -46,6 +46,15 @@ variable "install_method" {
 variable "rke2_channel" {
   default = "latest"
 }
+variable "enable_ipv6" {
+  description = "Give the nodes an IPv6 address for dual-stack or IPv6 clusters, the subnet needs an IPv6 cidr"
+  type        = bool
+  default     = false
+}
+variable "airgap_dir" {
+  description = "Local dir with the <version> dirs of airgap artifacts, when set the nodes install without downloading"
+  default     = ""
+}
 variable "server_flags" {}
 variable "worker_flags" {}
 variable "split_roles" {
@@ -17,6 +17,7 @@ resource "aws_instance" "worker" {
     volume_type = "standard"
   }
   subnet_id = var.subnets
+  ipv6_address_count = var.enable_ipv6 ? 1 : 0
   availability_zone = var.availability_zone
   vpc_security_group_ids = [
     var.sg_id
@@ -26,6 +27,13 @@ resource "aws_instance" "worker" {
     Name = "${var.resource_name}-worker"
     "kubernetes.io/cluster/clusterid" = "owned"
   }
+  provisioner "remote-exec" {
+    inline = ["mkdir -p /tmp/distros-airgap"]
+  }
+  provisioner "file" {
+    source      = var.airgap_dir != "" ? "${var.airgap_dir}/${var.rke2_version}/" : "../install/airgap/"
+    destination = "/tmp/distros-airgap"
+  }
   provisioner "file" {
     source = "../install/join_rke2_agent.sh"
     destination = "/tmp/join_rke2_agent.sh"
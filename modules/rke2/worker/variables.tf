@@ -27,4 +27,6 @@ variable "username" {
   default = "username"
 }
 variable "vpc_id" {}
-variable "worker_flags" {}
\ No newline at end of file
+variable "worker_flags" {}
+variable "airgap_dir" {}
+variable "enable_ipv6" {}
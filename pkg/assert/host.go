@@ -2,7 +2,6 @@ package assert
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/rancher/distros-test-framework/shared"
 
@@ -10,7 +9,7 @@ import (
 )
 
 // CheckComponentCmdHost runs a command on the host and asserts that the value
-// received contains the specified substring
+// received matches the specified matchers, plain values are matched as substring
 // you can send multiple asserts from a cmd but all of them must be true
 //
 // need to send KubeconfigFile
@@ -18,21 +17,51 @@ func CheckComponentCmdHost(cmd string, asserts ...string) error {
 	if cmd == "" {
 		return fmt.Errorf("cmd: %s should not be sent empty", cmd)
 	}
+	matchers, err := parseAsserts(asserts)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
+	}
+
 	Eventually(func() error {
-		res, err := shared.RunCommandHost(cmd)
+		result, err := shared.HostExecutor{}.Run(cmd)
 		Expect(err).ToNot(HaveOccurred())
-		for _, assert := range asserts {
-			if assert == "" {
-				return fmt.Errorf("assert: %s should not be sent empty", assert)
-			}
-			if !strings.Contains(res, assert) {
-				return fmt.Errorf("expected substring %q not found in result %q", assert, res)
+		if err = result.Err(); err != nil {
+			return err
+		}
+
+		res := assertedOutput(result)
+		for _, matcher := range matchers {
+			if err = matcher.Match(res); err != nil {
+				return err
 			}
 
-			fmt.Println("\nResult:", res+"\nMatched with:\n", assert)
+			fmt.Println("\nResult:", res+"\nMatched with:\n", matcher)
 		}
 		return nil
-	}, "420s", "5s").Should(Succeed())
+	}, cfg.Timeouts.Command, "5s").Should(Succeed())
 
 	return nil
 }
+
+// parseAsserts parses the asserts into matchers, empty asserts are not allowed.
+func parseAsserts(asserts []string) ([]Matcher, error) {
+	matchers := make([]Matcher, 0, len(asserts))
+	for _, assert := range asserts {
+		if assert == "" {
+			return nil, shared.ReturnLogError("asserts should not be sent empty")
+		}
+
+		m, err := ParseMatcher(assert)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
@@ -0,0 +1,409 @@
+package assert
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+// Matcher matches a command output against an expected value.
+//
+// Match returns nil when the output matches or an error describing why it did not.
+type Matcher interface {
+	Match(output string) error
+	String() string
+}
+
+// versionRegex matches version tokens not glued to a word, so go1.20.10 is not a version.
+var versionRegex = regexp.MustCompile(`(?:^|[^\w.])(v?\d+\.\d+(?:\.\d+)?(?:[-+][0-9A-Za-z.+-]*)?)`)
+
+type containsMatcher struct{ expected string }
+
+type notContainsMatcher struct{ expected string }
+
+type exactMatcher struct{ expected string }
+
+type regexMatcher struct {
+	expr *regexp.Regexp
+}
+
+type semverMatcher struct {
+	raw         string
+	constraints version.Constraints
+}
+
+type jsonPathMatcher struct {
+	path    string
+	matcher Matcher
+}
+
+type allOfMatcher struct{ matchers []Matcher }
+
+type anyOfMatcher struct{ matchers []Matcher }
+
+// ParseMatcher parses an expected value into a Matcher.
+//
+// exact(value)              output trimmed equals value
+//
+// contains(value)           output contains value, same as a plain value
+//
+// notContains(value)        output does not contain value
+//
+// regex(expression)         output matches the regular expression
+//
+// semver(>=1.7.3,<1.8)      first v prefixed version, or else first version, satisfies the constraints
+//
+// jsonpath(path, matcher)   json output value at path satisfies matcher, e.g. jsonpath(.a[0].b, exact(x))
+//
+// allOf(m1, m2...)          output satisfies all matchers
+//
+// anyOf(m1, m2...)          output satisfies at least one matcher
+func ParseMatcher(expr string) (Matcher, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, shared.ReturnLogError("expected value should not be empty")
+	}
+
+	name, args, ok := matcherCall(expr)
+	if !ok {
+		return &containsMatcher{expected: expr}, nil
+	}
+
+	switch name {
+	case "contains":
+		return &containsMatcher{expected: args}, nil
+	case "notContains":
+		return &notContainsMatcher{expected: args}, nil
+	case "exact":
+		return &exactMatcher{expected: args}, nil
+	case "regex":
+		r, err := regexp.Compile(args)
+		if err != nil {
+			return nil, shared.ReturnLogError("invalid regex: %s: %w", args, err)
+		}
+		return &regexMatcher{expr: r}, nil
+	case "semver":
+		c, err := version.NewConstraint(args)
+		if err != nil {
+			return nil, shared.ReturnLogError("invalid semver constraint: %s: %w", args, err)
+		}
+		return &semverMatcher{raw: args, constraints: c}, nil
+	case "jsonpath":
+		return parseJSONPathMatcher(args)
+	case "allOf", "anyOf":
+		matchers, err := parseMatchers(args)
+		if err != nil {
+			return nil, err
+		}
+		if name == "allOf" {
+			return &allOfMatcher{matchers: matchers}, nil
+		}
+		return &anyOfMatcher{matchers: matchers}, nil
+	default:
+		return nil, shared.ReturnLogError("unknown matcher: %s in %s", name, expr)
+	}
+}
+
+// SplitExpected splits comma separated expected values ignoring the commas inside matcher parentheses.
+func SplitExpected(values string) []string {
+	var parts []string
+	depth, start := 0, 0
+
+	for i, r := range values {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ',':
+			if depth == 0 {
+				parts = append(parts, values[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	return append(parts, values[start:])
+}
+
+// matcherCall returns the matcher name and raw args when the whole expr is in name(args) format,
+// the parenthesis opened after the name must be the one closing expr.
+func matcherCall(expr string) (name, args string, ok bool) {
+	open := strings.Index(expr, "(")
+	if open <= 0 || !strings.HasSuffix(expr, ")") {
+		return "", "", false
+	}
+
+	depth := 0
+	for i, r := range expr[open:] {
+		switch r {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth == 0 && open+i != len(expr)-1 {
+				return "", "", false
+			}
+		}
+	}
+	if depth != 0 {
+		return "", "", false
+	}
+
+	name = expr[:open]
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return "", "", false
+		}
+	}
+
+	return name, expr[open+1 : len(expr)-1], true
+}
+
+func parseMatchers(args string) ([]Matcher, error) {
+	var matchers []Matcher
+	for _, arg := range SplitExpected(args) {
+		m, err := ParseMatcher(arg)
+		if err != nil {
+			return nil, err
+		}
+		matchers = append(matchers, m)
+	}
+
+	return matchers, nil
+}
+
+func parseJSONPathMatcher(args string) (Matcher, error) {
+	parts := SplitExpected(args)
+	if len(parts) < 2 {
+		return nil, shared.ReturnLogError("jsonpath should have a path and a matcher: %s", args)
+	}
+
+	m, err := ParseMatcher(strings.Join(parts[1:], ","))
+	if err != nil {
+		return nil, err
+	}
+
+	return &jsonPathMatcher{path: strings.TrimSpace(parts[0]), matcher: m}, nil
+}
+
+func (m *containsMatcher) Match(output string) error {
+	if !strings.Contains(output, m.expected) {
+		return fmt.Errorf("%s failed: %q not found in output: %q", m, m.expected, output)
+	}
+
+	return nil
+}
+
+func (m *containsMatcher) String() string { return "contains(" + m.expected + ")" }
+
+func (m *notContainsMatcher) Match(output string) error {
+	if strings.Contains(output, m.expected) {
+		return fmt.Errorf("%s failed: %q found in output: %q", m, m.expected, output)
+	}
+
+	return nil
+}
+
+func (m *notContainsMatcher) String() string { return "notContains(" + m.expected + ")" }
+
+func (m *exactMatcher) Match(output string) error {
+	if strings.TrimSpace(output) != m.expected {
+		return fmt.Errorf("%s failed: output %q is not equal to %q", m, strings.TrimSpace(output), m.expected)
+	}
+
+	return nil
+}
+
+func (m *exactMatcher) String() string { return "exact(" + m.expected + ")" }
+
+func (m *regexMatcher) Match(output string) error {
+	if !m.expr.MatchString(output) {
+		return fmt.Errorf("%s failed: no match on output: %q", m, output)
+	}
+
+	return nil
+}
+
+func (m *regexMatcher) String() string { return "regex(" + m.expr.String() + ")" }
+
+func (m *semverMatcher) Match(output string) error {
+	raw := componentVersion(output)
+	if raw == "" {
+		return fmt.Errorf("%s failed: no version found on output: %q", m, output)
+	}
+
+	v, err := version.NewVersion(raw)
+	if err != nil {
+		return fmt.Errorf("%s failed: invalid version %s on output: %q", m, raw, output)
+	}
+	// distro suffixes like -k3s1 are not prereleases, so constraints check the core version.
+	if !m.constraints.Check(v.Core()) {
+		return fmt.Errorf("%s failed: version %s does not satisfy constraints on output: %q", m, raw, output)
+	}
+
+	return nil
+}
+
+func (m *semverMatcher) String() string { return "semver(" + m.raw + ")" }
+
+func (m *jsonPathMatcher) Match(output string) error {
+	var data interface{}
+	if err := json.Unmarshal([]byte(output), &data); err != nil {
+		return fmt.Errorf("%s failed: output is not json: %w", m, err)
+	}
+
+	values, err := jsonPathValues(data, m.path)
+	if err != nil {
+		return fmt.Errorf("%s failed: %w", m, err)
+	}
+
+	if err = m.matcher.Match(strings.Join(values, " ")); err != nil {
+		return fmt.Errorf("%s failed on path %s: %w", m, m.path, err)
+	}
+
+	return nil
+}
+
+func (m *jsonPathMatcher) String() string {
+	return "jsonpath(" + m.path + ", " + m.matcher.String() + ")"
+}
+
+func (m *allOfMatcher) Match(output string) error {
+	for _, matcher := range m.matchers {
+		if err := matcher.Match(output); err != nil {
+			return fmt.Errorf("%s failed: %w", m, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *allOfMatcher) String() string { return "allOf(" + joinMatchers(m.matchers) + ")" }
+
+func (m *anyOfMatcher) Match(output string) error {
+	var errs []string
+	for _, matcher := range m.matchers {
+		err := matcher.Match(output)
+		if err == nil {
+			return nil
+		}
+		errs = append(errs, err.Error())
+	}
+
+	return fmt.Errorf("%s failed: %s", m, strings.Join(errs, "; "))
+}
+
+func (m *anyOfMatcher) String() string { return "anyOf(" + joinMatchers(m.matchers) + ")" }
+
+// componentVersion returns the first v prefixed version on output or else the first version,
+// skipping ip addresses and cidrs.
+func componentVersion(output string) string {
+	var first string
+	for _, loc := range versionRegex.FindAllStringSubmatchIndex(output, -1) {
+		start, end := loc[2], loc[3]
+		if end < len(output) && strings.ContainsAny(output[end:end+1], "./") {
+			continue
+		}
+
+		token := output[start:end]
+		if strings.HasPrefix(token, "v") {
+			return token
+		}
+		if first == "" {
+			first = token
+		}
+	}
+
+	return first
+}
+
+func joinMatchers(matchers []Matcher) string {
+	names := make([]string, 0, len(matchers))
+	for _, m := range matchers {
+		names = append(names, m.String())
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// jsonPathValues returns the values found on path, supporting keys, [index] and [*].
+//
+// path can be written as .a.b[0].c, $.a.b[0].c or {.a.b[0].c}.
+func jsonPathValues(data interface{}, path string) ([]string, error) {
+	path = strings.TrimSuffix(strings.TrimPrefix(path, "{"), "}")
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".[")
+
+	nodes := []interface{}{data}
+	for _, segment := range strings.Split(path, ".") {
+		if segment == "" {
+			continue
+		}
+
+		var next []interface{}
+		for _, node := range nodes {
+			found, err := jsonPathSegment(node, segment)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, found...)
+		}
+		nodes = next
+	}
+
+	values := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		switch v := node.(type) {
+		case string:
+			values = append(values, v)
+		default:
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, string(raw))
+		}
+	}
+
+	return values, nil
+}
+
+func jsonPathSegment(node interface{}, segment string) ([]interface{}, error) {
+	if !strings.HasPrefix(segment, "[") {
+		obj, ok := node.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("key %s on non object value", segment)
+		}
+		value, ok := obj[segment]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found", segment)
+		}
+
+		return []interface{}{value}, nil
+	}
+
+	list, ok := node.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("index %s on non array value", segment)
+	}
+
+	index := strings.TrimSuffix(strings.TrimPrefix(segment, "["), "]")
+	if index == "*" {
+		return list, nil
+	}
+
+	i, err := strconv.Atoi(index)
+	if err != nil || i < 0 || i >= len(list) {
+		return nil, fmt.Errorf("invalid index %s for array of length %d", index, len(list))
+	}
+
+	return []interface{}{list[i]}, nil
+}
@@ -0,0 +1,125 @@
+package assert
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMatcher(t *testing.T) {
+	tests := []struct {
+		expr    string
+		want    string
+		wantErr bool
+	}{
+		{expr: "v1.27.2", want: "contains(v1.27.2)"},
+		{expr: "  Running ", want: "contains(Running)"},
+		{expr: "contains(a,b)", want: "contains(a,b)"},
+		{expr: "notContains(Error)", want: "notContains(Error)"},
+		{expr: "exact(v1.27.2+k3s1)", want: "exact(v1.27.2+k3s1)"},
+		{expr: "regex(^v1\\.2[0-9])", want: "regex(^v1\\.2[0-9])"},
+		{expr: "semver(>=1.7.3,<1.8)", want: "semver(>=1.7.3,<1.8)"},
+		{expr: "jsonpath(.a[0].b, exact(x))", want: "jsonpath(.a[0].b, exact(x))"},
+		{expr: "allOf(a, notContains(b))", want: "allOf(contains(a), notContains(b))"},
+		{expr: "anyOf(exact(a),regex(b+))", want: "anyOf(exact(a), regex(b+))"},
+		{expr: "exact(a)b", want: "contains(exact(a)b)"},
+		{expr: "func(x) (y)", want: "contains(func(x) (y))"},
+		{expr: ""},
+		{expr: "unknown(a)", wantErr: true},
+		{expr: "regex([)", wantErr: true},
+		{expr: "semver(not a version)", wantErr: true},
+		{expr: "jsonpath(.a)", wantErr: true},
+		{expr: "allOf(a, unknown(b))", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		m, err := ParseMatcher(tt.expr)
+		if tt.wantErr || tt.expr == "" {
+			if err == nil {
+				t.Errorf("ParseMatcher(%q) = %s, want error", tt.expr, m)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMatcher(%q) error: %v", tt.expr, err)
+			continue
+		}
+		if m.String() != tt.want {
+			t.Errorf("ParseMatcher(%q) = %s, want %s", tt.expr, m, tt.want)
+		}
+	}
+}
+
+func TestSplitExpected(t *testing.T) {
+	tests := []struct {
+		values string
+		want   []string
+	}{
+		{values: "a", want: []string{"a"}},
+		{values: "a,b", want: []string{"a", "b"}},
+		{values: "semver(>=1.7.3,<1.8),exact(v1)", want: []string{"semver(>=1.7.3,<1.8)", "exact(v1)"}},
+		{values: "allOf(a,anyOf(b,c)),d", want: []string{"allOf(a,anyOf(b,c))", "d"}},
+		{values: "a),b", want: []string{"a)", "b"}},
+		{values: "", want: []string{""}},
+	}
+
+	for _, tt := range tests {
+		if got := SplitExpected(tt.values); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitExpected(%q) = %q, want %q", tt.values, got, tt.want)
+		}
+	}
+}
+
+func TestMatcherMatch(t *testing.T) {
+	rke2Version := "rke2 version v1.27.2+rke2r1 (3f2a8f1)\ngo version go1.20.10 X:boringcrypto"
+	containerd := "containerd github.com/k3s-io/containerd v1.7.3-k3s1 7880925980b1"
+	pods := `{"items":[{"status":{"phase":"Running"}},{"status":{"phase":"Pending"}}]}`
+
+	tests := []struct {
+		expr   string
+		output string
+		match  bool
+	}{
+		{expr: "v1.2", output: "v1.20.1", match: true},
+		{expr: "notContains(Error)", output: "CrashLoopBackOff Error", match: false},
+		{expr: "exact(v1.27.2)", output: " v1.27.2\n", match: true},
+		{expr: "exact(v1.27)", output: "v1.27.2", match: false},
+		{expr: "regex(^v1\\.27\\.[0-9]+$)", output: "v1.27.2", match: true},
+
+		{expr: "semver(>=1.27,<1.28)", output: rke2Version, match: true},
+		{expr: "semver(>=1.20,<1.21)", output: rke2Version, match: false},
+		{expr: "semver(>=1.7.3,<1.8)", output: containerd, match: true},
+		{expr: "semver(>=1.7.3,<1.8)", output: "containerd 1.7.11-k3s2", match: true},
+		{expr: "semver(>=1.7.3,<1.8)", output: "containerd 1.6.2 go1.7.5", match: false},
+		{expr: "semver(>=10.0)", output: "pod cidr 10.42.0.0/16", match: false},
+		{expr: "semver(>=10.0)", output: "node ip 10.42.0.1 ready", match: false},
+		{expr: "semver(>=1.0)", output: "no version here", match: false},
+		{expr: "semver(>=1.15)", output: "cilium v1.15.5 chart 1.16.0", match: true},
+
+		{expr: "jsonpath(.items[0].status.phase, exact(Running))", output: pods, match: true},
+		{expr: "jsonpath(.items[*].status.phase, contains(Pending))", output: pods, match: true},
+		{expr: "jsonpath($.items[1].status.phase, exact(Running))", output: pods, match: false},
+		{expr: "jsonpath({.items[2].status}, contains(x))", output: pods, match: false},
+		{expr: "jsonpath(.items, contains(x))", output: "not json", match: false},
+
+		{expr: "allOf(v1.27, notContains(rc))", output: "v1.27.2", match: true},
+		{expr: "allOf(v1.27, notContains(rc))", output: "v1.27.2-rc1", match: false},
+		{expr: "anyOf(exact(a), exact(b))", output: "b", match: true},
+		{expr: "anyOf(exact(a), exact(b))", output: "c", match: false},
+	}
+
+	for _, tt := range tests {
+		m, err := ParseMatcher(tt.expr)
+		if err != nil {
+			t.Errorf("ParseMatcher(%q) error: %v", tt.expr, err)
+			continue
+		}
+
+		err = m.Match(tt.output)
+		if tt.match && err != nil {
+			t.Errorf("%s on %q: %v", m, tt.output, err)
+		}
+		if !tt.match && err == nil {
+			t.Errorf("%s on %q: matched, want no match", m, tt.output)
+		}
+	}
+}
@@ -5,39 +5,61 @@ import (
 	"strings"
 
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/pkg/suc"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
 
-type NodeAssertFunc func(g Gomega, node shared.Node)
+type NodeAssertFunc func(g Gomega, node kube.Node)
 
 // NodeAssertVersionTypeUpgrade  custom assertion func that asserts that node version is as expected
 func NodeAssertVersionTypeUpgrade(c customflag.FlagConfig) NodeAssertFunc {
-	if c.InstallMode.Version != "" {
-		return assertVersion(c)
-	} else if c.InstallMode.Commit != "" {
-		return assertCommit(c)
+	return NodeAssertUpgradedTo(c.InstallMode.String())
+}
+
+// NodeAssertUpgradedTo custom assertion func that asserts that node is on the version or commit,
+// used to check each hop of an upgrade path.
+func NodeAssertUpgradedTo(versionOrCommit string) NodeAssertFunc {
+	if strings.HasPrefix(versionOrCommit, "v") {
+		return assertVersion(versionOrCommit)
+	} else if strings.HasPrefix(versionOrCommit, "http") {
+		return assertChannelVersion()
+	} else if versionOrCommit != "" {
+		return assertCommit(versionOrCommit)
 	}
 
-	return func(g Gomega, node shared.Node) {
+	return func(g Gomega, node kube.Node) {
 		GinkgoT().Errorf("no version or commit specified for upgrade assertion")
 	}
 }
 
 // assertVersion returns the NodeAssertFunc for asserting version
-func assertVersion(c customflag.FlagConfig) NodeAssertFunc {
-	fmt.Printf("Asserting Version: %s\n", c.InstallMode.Version)
-	return func(g Gomega, node shared.Node) {
-		version := strings.Split(c.InstallMode.Version, "-")
+func assertVersion(expected string) NodeAssertFunc {
+	fmt.Printf("Asserting Version: %s\n", expected)
+	return func(g Gomega, node kube.Node) {
+		version := strings.Split(expected, "-")
 		g.Expect(node.Version).Should(ContainSubstring(version[0]),
 			"Nodes should all be upgraded to the specified version", node.Name)
 	}
 }
 
+// assertChannelVersion returns the NodeAssertFunc for asserting the version
+// the upgrade plans resolved the channel URL to.
+func assertChannelVersion() NodeAssertFunc {
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred(), "error getting product: %v", err)
+
+	version, err := suc.LatestVersion(product)
+	Expect(err).NotTo(HaveOccurred(), "error getting channel version: %v", err)
+
+	return assertVersion(version)
+}
+
 // assertCommit returns the NodeAssertFunc for asserting commit
-func assertCommit(c customflag.FlagConfig) NodeAssertFunc {
+func assertCommit(expected string) NodeAssertFunc {
 	product, err := shared.GetProduct()
 	Expect(err).NotTo(HaveOccurred(), "error getting product: %v", err)
 
@@ -48,16 +70,16 @@ func assertCommit(c customflag.FlagConfig) NodeAssertFunc {
 	ending := strings.Index(commit, ")")
 	commit = commit[initial+1 : ending]
 
-	fmt.Printf("Asserting Commit: %s\n", c.InstallMode.Commit)
-	return func(g Gomega, node shared.Node) {
-		g.Expect(c.InstallMode.Commit).Should(ContainSubstring(commit),
+	fmt.Printf("Asserting Commit: %s\n", expected)
+	return func(g Gomega, node kube.Node) {
+		g.Expect(expected).Should(ContainSubstring(commit),
 			"Nodes should all be upgraded to the specified commit", node.Name)
 	}
 }
 
 // NodeAssertVersionUpgraded custom assertion func that asserts that node version is as expected
 func NodeAssertVersionUpgraded() NodeAssertFunc {
-	return func(g Gomega, node shared.Node) {
+	return func(g Gomega, node kube.Node) {
 		version := strings.Split(customflag.ServiceFlag.SUCUpgradeVersion.String(), "-")
 		g.Expect(node.Version).Should(ContainSubstring(version[0]),
 			"Nodes should all be upgraded to the specified version", node.Name)
@@ -66,37 +88,45 @@ func NodeAssertVersionUpgraded() NodeAssertFunc {
 
 // NodeAssertReadyStatus custom assertion func that asserts that the node is in Ready state.
 func NodeAssertReadyStatus() NodeAssertFunc {
-	return func(g Gomega, node shared.Node) {
+	return func(g Gomega, node kube.Node) {
 		g.Expect(node.Status).Should(Equal("Ready"),
 			"Nodes should all be in Ready state")
 	}
 }
 
 // CheckComponentCmdNode runs a command on a node and asserts that the value received
-// contains the specified substring.
+// matches the specified matchers, plain values are matched as substring.
 func CheckComponentCmdNode(cmd, ip string, asserts ...string) error {
 	if cmd == "" {
 		return shared.ReturnLogError("cmd should not be sent empty")
 	}
-	for _, assert := range asserts {
-		if assert == "" {
-			return shared.ReturnLogError("asserts should not be sent empty")
-		}
+
+	matchers, err := parseAsserts(asserts)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
 	}
 
 	Eventually(func(g Gomega) error {
 		fmt.Println("\nExecuting cmd: ", cmd)
-		res, err := shared.RunCommandOnNode(cmd, ip)
+		result, err := shared.SSHExecutor{IP: ip}.Run(cmd)
 		Expect(err).ToNot(HaveOccurred())
+		g.Expect(result.ExitCode).To(Equal(0), "command: %s failed: %s", cmd, result.Stderr)
+
+		res := assertedOutput(result)
 
-		for _, assert := range asserts {
-			g.Expect(res).Should(ContainSubstring(assert))
-			fmt.Println("\nResult:\n", res+"\nMatched with:\n", assert)
+		for _, matcher := range matchers {
+			g.Expect(matcher.Match(res)).To(Succeed())
+			fmt.Println("\nResult:\n", res+"\nMatched with:\n", matcher)
 		}
 
 		return nil
 
-	}, "420s", "5s").Should(Succeed())
+	}, cfg.Timeouts.Command, "5s").Should(Succeed())
 
 	return nil
 }
@@ -2,27 +2,28 @@ package assert
 
 import (
 	"fmt"
+	"net"
 	"strings"
 
 	"github.com/onsi/gomega/types"
+	"github.com/rancher/distros-test-framework/pkg/kube"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/gomega"
 )
 
-type PodAssertFunc func(g Gomega, pod shared.Pod)
+type PodAssertFunc func(g Gomega, pod kube.Pod)
 
 var statusCompleted = "Completed"
 
 // PodAssertRestart custom assertion func that asserts that pods are not restarting with no reason
 // controller, scheduler, helm-install pods can be restarted occasionally when cluster started if only once
 func PodAssertRestart() PodAssertFunc {
-	return func(g Gomega, pod shared.Pod) {
+	return func(g Gomega, pod kube.Pod) {
 		if strings.Contains(pod.NameSpace, "kube-system") &&
 			strings.Contains(pod.Name, "controller") &&
 			strings.Contains(pod.Name, "scheduler") {
-			g.Expect(pod.Restarts).Should(SatisfyAny(Equal("0"),
-				Equal("1")),
+			g.Expect(pod.Restarts).Should(BeNumerically("<=", 1),
 				"could be restarted occasionally when cluster started", pod.Name)
 		}
 	}
@@ -31,7 +32,7 @@ func PodAssertRestart() PodAssertFunc {
 // PodAssertReady custom assertion func that asserts that the pod is
 // with correct numbers of ready containers.
 func PodAssertReady() PodAssertFunc {
-	return func(g Gomega, pod shared.Pod) {
+	return func(g Gomega, pod kube.Pod) {
 		g.ExpectWithOffset(1, pod.Ready).To(checkReadyFields(),
 			"should have equal values in n/n format")
 	}
@@ -55,7 +56,7 @@ func checkReadyFields() types.GomegaMatcher {
 // PodAssertStatus custom assertion that asserts that pod status is completed or in some cases
 // apply pods can have an error status
 func PodAssertStatus() PodAssertFunc {
-	return func(g Gomega, pod shared.Pod) {
+	return func(g Gomega, pod kube.Pod) {
 		if strings.Contains(pod.Name, "helm-install") {
 			g.Expect(pod.Status).Should(Equal(statusCompleted), pod.Name)
 		} else if strings.Contains(pod.Name, "apply") &&
@@ -81,19 +82,27 @@ func CheckPodStatusRunning(name, namespace, assert string) {
 	}, "180s", "5s").Should(Succeed())
 }
 
-// ValidatePodIPByLabel validates expected pod IP by label
+// ValidatePodIPByLabel validates the pods of each label have an ip in each of the expected cidrs,
+// expected holds comma separated cidrs per label, one per ip family, e.g. "10.42.0.0/16,2001:cafe:42::/56".
 func ValidatePodIPByLabel(labels, expected []string) {
 	Eventually(func() error {
 		for i, label := range labels {
-			if len(labels) > 0 {
-				res, _ := shared.KubectlCommand(
-					"host",
-					"get",
-					fmt.Sprintf("pods -l %s", label),
-					`-o=jsonpath='{range .items[*]}{.status.podIPs[*].ip}{" "}{end}'`)
-				ips := strings.Split(res, " ")
-				if strings.Contains(ips[0], expected[i]) {
-					return nil
+			cidrs, err := parseCIDRs(expected[i])
+			if err != nil {
+				return err
+			}
+
+			pods, err := kube.GetPods("", label)
+			if err != nil {
+				return err
+			}
+			if len(pods) == 0 {
+				return fmt.Errorf("no pods found with label %s", label)
+			}
+
+			for _, pod := range pods {
+				if err = ipsInCIDRs(pod.PodIPs, cidrs); err != nil {
+					return fmt.Errorf("pod %s/%s: %w", pod.NameSpace, pod.Name, err)
 				}
 			}
 		}
@@ -101,3 +110,34 @@ func ValidatePodIPByLabel(labels, expected []string) {
 	}, "180s", "30s").Should(Succeed(),
 		"failed to validate expected: %s on %s", expected, labels)
 }
+
+func parseCIDRs(value string) ([]*net.IPNet, error) {
+	var cidrs []*net.IPNet
+	for _, c := range strings.Split(value, ",") {
+		_, cidr, err := net.ParseCIDR(strings.TrimSpace(c))
+		if err != nil {
+			return nil, shared.ReturnLogError("invalid cidr %s: %w", c, err)
+		}
+		cidrs = append(cidrs, cidr)
+	}
+
+	return cidrs, nil
+}
+
+// ipsInCIDRs returns an error naming the first cidr none of the ips belongs to.
+func ipsInCIDRs(ips []string, cidrs []*net.IPNet) error {
+	for _, cidr := range cidrs {
+		found := false
+		for _, ip := range ips {
+			if cidr.Contains(net.ParseIP(ip)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("none of the ips %v in %s", ips, cidr)
+		}
+	}
+
+	return nil
+}
@@ -2,20 +2,29 @@ package assert
 
 import (
 	"fmt"
-	"strings"
 	"time"
 
 	"github.com/rancher/distros-test-framework/shared"
 )
 
 // validate calls runAssertion for each cmd/assert pair
-func validate(exec func(string) (string, error), args ...string) error {
+func validate(executor shared.Executor, args ...string) error {
 	if len(args) < 2 || len(args)%2 != 0 {
 		return shared.ReturnLogError("should send even number of args")
 	}
 
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	commandTimeout, err := time.ParseDuration(cfg.Timeouts.Command)
+	if err != nil {
+		return shared.ReturnLogError("invalid command timeout: %w", err)
+	}
+
 	errorsChan := make(chan error, len(args)/2)
-	timeout := time.After(420 * time.Second)
+	timeout := time.After(commandTimeout)
 	ticker := time.NewTicker(3 * time.Second)
 
 	for i := 0; i < len(args); i++ {
@@ -29,7 +38,13 @@ func validate(exec func(string) (string, error), args ...string) error {
 					"and/or cmd:%s",
 					assert, cmd)
 			}
-			err := runAssertion(cmd, assert, exec, ticker.C, timeout, errorsChan)
+			matcher, err := ParseMatcher(assert)
+			if err != nil {
+				close(errorsChan)
+				return err
+			}
+
+			err = runAssertion(cmd, matcher, executor, ticker.C, timeout, errorsChan)
 			if err != nil {
 				shared.LogLevel("error", "error from runAssertion():\n %s\n", err)
 				close(errorsChan)
@@ -42,36 +57,42 @@ func validate(exec func(string) (string, error), args ...string) error {
 	return nil
 }
 
-// runAssertion runs a command and asserts that the value received against his respective command
+// runAssertion runs a command and asserts that the value received matches his respective matcher
 func runAssertion(
-	cmd, assert string,
-	exec func(string) (string, error),
+	cmd string,
+	matcher Matcher,
+	executor shared.Executor,
 	ticker <-chan time.Time,
 	timeout <-chan time.Time,
 	errorsChan chan<- error,
 ) error {
 	for {
-		res, err := exec(cmd)
+		result, err := executor.Run(cmd)
 		if err != nil {
 			errorsChan <- err
-			return fmt.Errorf("error from runCmd: %s\n %s", cmd, res)
+			return fmt.Errorf("error from runCmd: %s\n %w", cmd, err)
 		}
+		if err = result.Err(); err != nil {
+			errorsChan <- err
+			return fmt.Errorf("error from runCmd: %w", err)
+		}
+		res := assertedOutput(result)
 
 		select {
 		case <-timeout:
 			timeoutErr := shared.ReturnLogError("timeout reached for command:\n%s\n "+
-				"Trying to assert with:\n %s",
-				cmd, res)
+				"Trying to assert with:\n %s\n%v",
+				cmd, matcher, matcher.Match(res))
 			errorsChan <- timeoutErr
 			return timeoutErr
 
 		case <-ticker:
-			if strings.Contains(res, assert) {
+			if matcher.Match(res) == nil {
 				fmt.Printf("\nCommand:\n"+
 					"%s"+
 					"\n---------------------\nAssertion:\n"+
 					"%s"+
-					"\n----------------------\nMatched with result:\n%s\n", cmd, assert, res)
+					"\n----------------------\nMatched with result:\n%s\n", cmd, matcher, res)
 				errorsChan <- nil
 				return nil
 			}
@@ -79,21 +100,25 @@ func runAssertion(
 	}
 }
 
+// assertedOutput returns the output matched by the asserts, stdout and stderr combined on nodes
+// as RunCommandOnNode returns it, stdout on the host as RunCommandHost does.
+func assertedOutput(r *shared.CmdResult) string {
+	if r.NodeIP == "" {
+		return r.Output()
+	}
+
+	return r.CombinedOutput()
+}
+
 // ValidateOnHost runs an exec function on RunCommandHost and assert given is fulfilled.
 // The last argument should be the assertion.
 // Need to send kubeconfig file.
 func ValidateOnHost(args ...string) error {
-	exec := func(cmd string) (string, error) {
-		return shared.RunCommandHost(cmd)
-	}
-	return validate(exec, args...)
+	return validate(shared.HostExecutor{}, args...)
 }
 
-// ValidateOnNode runs an exec function on RunCommandHost and assert given is fulfilled.
+// ValidateOnNode runs an exec function on RunCommandOnNode and assert given is fulfilled.
 // The last argument should be the assertion.
 func ValidateOnNode(ip string, args ...string) error {
-	exec := func(cmd string) (string, error) {
-		return shared.RunCommandOnNode(cmd, ip)
-	}
-	return validate(exec, args...)
+	return validate(shared.SSHExecutor{IP: ip}, args...)
 }
@@ -0,0 +1,59 @@
+package customflag
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/config"
+)
+
+// AddConfigDefaults fills the flags that were not passed in with the values from the config file.
+func AddConfigDefaults(cfg *config.ProductConfig) error {
+	if cfg == nil {
+		return nil
+	}
+
+	values := []struct {
+		current string
+		value   string
+		set     func(string) error
+	}{
+		{ServiceFlag.InstallMode.String(), cfg.Upgrade.InstallVersionOrCommit, ServiceFlag.InstallMode.Set},
+		{ServiceFlag.Channel.String(), cfg.Upgrade.Channel, ServiceFlag.Channel.Set},
+		{ServiceFlag.SUCUpgradeVersion.String(), cfg.Upgrade.SUCUpgradeVersion, ServiceFlag.SUCUpgradeVersion.Set},
+		{ServiceFlag.SonobouyVersion.String(), cfg.Tests.SonobuoyVersion, ServiceFlag.SonobouyVersion.Set},
+		{TestCaseNameFlag.String(), strings.Join(cfg.Tests.Cases, ","), TestCaseNameFlag.Set},
+	}
+
+	for _, v := range values {
+		if v.current != "" || v.value == "" {
+			continue
+		}
+		if err := v.set(v.value); err != nil {
+			return err
+		}
+	}
+
+	if ServiceFlag.TestConfig.WorkloadName == "" {
+		ServiceFlag.TestConfig.WorkloadName = cfg.Tests.WorkloadName
+	}
+	if ServiceFlag.TestConfig.Plan == "" {
+		ServiceFlag.TestConfig.Plan = cfg.Tests.Plan
+	}
+	if ServiceFlag.TestConfig.Description == "" {
+		ServiceFlag.TestConfig.Description = cfg.Tests.Description
+	}
+
+	// bool flags take the config value only when not passed, so -destroy=false overrides destroy: true.
+	passed := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { passed[f.Name] = true })
+
+	if !passed["deployWorkload"] {
+		ServiceFlag.TestConfig.DeployWorkload = cfg.Tests.DeployWorkload
+	}
+	if !passed["destroy"] {
+		ServiceFlag.ClusterConfig.Destroy = destroyFlag(cfg.Destroy)
+	}
+
+	return nil
+}
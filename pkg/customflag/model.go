@@ -8,8 +8,11 @@ import (
 	"github.com/rancher/distros-test-framework/shared"
 )
 
+const airgapPrefix = "airgap:"
+
 var ServiceFlag FlagConfig
 var TestCaseNameFlag stringSlice
+var CertServicesFlag stringSlice
 
 // FlagConfig is a type that wraps all the flags that can be used
 type FlagConfig struct {
@@ -23,11 +26,14 @@ type FlagConfig struct {
 
 type sucUpgradeVersion struct {
 	SucUpgradeVersion string
+	Hops              []string
 }
 
 type installModeFlag struct {
 	Version string
 	Commit  string
+	Hops    []string
+	Airgap  bool
 }
 
 type channelFlag struct {
@@ -40,6 +46,7 @@ type testConfigFlag struct {
 	DeployWorkload bool
 	WorkloadName   string
 	Description    string
+	Plan           string
 }
 
 type externalConfigFlag struct {
@@ -95,20 +102,41 @@ func (c *channelFlag) Set(value string) error {
 }
 
 func (i *installModeFlag) String() string {
+	if i.Airgap {
+		return airgapPrefix + i.Version
+	}
+
 	return fmt.Sprintf("%s%s", i.Version, i.Commit)
 }
 
+// Set accepts a version or commit, or an ordered comma separated list of them as upgrade hops.
+// Prefixed with airgap: the versions are installed from the staged airgap artifacts.
+//
+// Version or Commit holds the last hop, the final target.
 func (i *installModeFlag) Set(value string) error {
-	if strings.HasPrefix(value, "v") {
-		if !strings.Contains(value, "k3s") && !strings.Contains(value, "rke2") {
-			return shared.ReturnLogError("invalid version format: %s", value)
+	i.Version, i.Commit, i.Hops = "", "", nil
+	i.Airgap = strings.HasPrefix(value, airgapPrefix)
+	value = strings.TrimPrefix(value, airgapPrefix)
+
+	for _, hop := range strings.Split(value, ",") {
+		hop = strings.TrimSpace(hop)
+		if strings.HasPrefix(hop, "v") {
+			if !strings.Contains(hop, "k3s") && !strings.Contains(hop, "rke2") {
+				return shared.ReturnLogError("invalid version format: %s", hop)
+			}
+		} else if i.Airgap {
+			return shared.ReturnLogError("airgap install mode only accepts versions: %s", hop)
+		} else if len(hop) != 40 {
+			return shared.ReturnLogError("invalid commit length: %s", hop)
 		}
-		i.Version = value
+		i.Hops = append(i.Hops, hop)
+	}
+
+	last := i.Hops[len(i.Hops)-1]
+	if strings.HasPrefix(last, "v") {
+		i.Version = last
 	} else {
-		if len(value) != 40 {
-			return shared.ReturnLogError("invalid commit length: %s", value)
-		}
-		i.Commit = value
+		i.Commit = last
 	}
 
 	return nil
@@ -118,12 +146,22 @@ func (t *sucUpgradeVersion) String() string {
 	return t.SucUpgradeVersion
 }
 
+// Set accepts a version or an ordered comma separated list of versions as upgrade hops.
+//
+// SucUpgradeVersion holds the last hop, the final target.
 func (t *sucUpgradeVersion) Set(value string) error {
-	if !strings.HasPrefix(value, "v") ||
-		(!strings.Contains(value, "k3s") && !strings.Contains(value, "rke2")) {
-		return shared.ReturnLogError("suc upgrade only accepts version format: %s", value)
+	t.Hops = nil
+
+	for _, hop := range strings.Split(value, ",") {
+		hop = strings.TrimSpace(hop)
+		isChannel := strings.HasPrefix(hop, "https://") || strings.HasPrefix(hop, "http://")
+		if !isChannel && (!strings.HasPrefix(hop, "v") ||
+			(!strings.Contains(hop, "k3s") && !strings.Contains(hop, "rke2"))) {
+			return shared.ReturnLogError("suc upgrade only accepts version or channel URL format: %s", hop)
+		}
+		t.Hops = append(t.Hops, hop)
 	}
-	t.SucUpgradeVersion = value
+	t.SucUpgradeVersion = t.Hops[len(t.Hops)-1]
 
 	return nil
 }
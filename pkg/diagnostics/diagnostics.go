@@ -0,0 +1,180 @@
+package diagnostics
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const maxNameLength = 80
+
+var nameRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// nodeFiles returns the files collected from each node by file name and command.
+func nodeFiles(product string) map[string]string {
+	return map[string]string{
+		"journal.log":    fmt.Sprintf("sudo journalctl -u '%s*' --no-pager", product),
+		"containerd.log": fmt.Sprintf("sudo cat /var/lib/rancher/%s/agent/containerd/containerd.log", product),
+		"kubelet.log": fmt.Sprintf("sudo cat /var/lib/rancher/%s/agent/logs/kubelet.log "+
+			"|| sudo journalctl -u '%s*' --no-pager | grep -i kubelet", product, product),
+		"config.yaml": fmt.Sprintf("sudo cat /etc/rancher/%s/config.yaml", product),
+	}
+}
+
+// Collect gathers the nodes logs, product config, events and describe of not ready
+// nodes and pods into a redacted tar.gz bundle on the artifact dir and returns its path.
+//
+// It should be called when a spec fails, before the cluster is destroyed.
+func Collect(spec string) (string, error) {
+	cluster := factory.GetCluster()
+	if cluster == nil {
+		return "", shared.ReturnLogError("no cluster to collect diagnostics from")
+	}
+
+	product, err := shared.GetProduct()
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := shared.ArtifactDir()
+	if err != nil {
+		return "", err
+	}
+
+	files := map[string]string{}
+	for _, ip := range append(append([]string{}, cluster.ServerIPs...), cluster.AgentIPs...) {
+		for name, cmd := range nodeFiles(product) {
+			files[filepath.Join("nodes", ip, name)] = collectOnNode(cmd, ip)
+		}
+	}
+
+	for name, content := range collectCluster() {
+		files[filepath.Join("cluster", name)] = content
+	}
+
+	// the diagnostics commands should not show on the next spec report.
+	shared.TakeTranscript()
+
+	path := filepath.Join(dir, "diagnostics", bundleName(spec))
+	if err = writeBundle(path, files); err != nil {
+		return "", err
+	}
+	shared.LogLevel("info", "diagnostics bundle written to %s", path)
+
+	return path, nil
+}
+
+func collectOnNode(cmd, ip string) string {
+	res, err := shared.SSHExecutor{IP: ip}.Run(cmd)
+	if err != nil {
+		return fmt.Sprintf("failed to run %s: %v\n", cmd, err)
+	}
+	if !res.Success() {
+		return fmt.Sprintf("%s\n%v\n", res.Stdout, res.Err())
+	}
+
+	return res.Stdout
+}
+
+// collectCluster returns the events and the describe of nodes not ready and pods not running.
+func collectCluster() map[string]string {
+	kubectl := func(args string) string {
+		res, err := shared.HostExecutor{}.Run("kubectl " + args + " --kubeconfig=" + shared.KubeConfigFile)
+		if err != nil {
+			return err.Error()
+		}
+		if !res.Success() {
+			return res.Stdout + "\n" + res.Err().Error()
+		}
+
+		return res.Stdout
+	}
+
+	files := map[string]string{
+		"events.log": kubectl("get events -A --sort-by=.lastTimestamp"),
+		"nodes.log":  kubectl("get nodes -o wide"),
+		"pods.log":   kubectl("get pods -A -o wide"),
+	}
+
+	var describe strings.Builder
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		describe.WriteString(err.Error() + "\n")
+	}
+	for _, n := range nodes {
+		if n.Status != "Ready" {
+			describe.WriteString(kubectl("describe node " + n.Name))
+		}
+	}
+	files["describe-nodes.log"] = describe.String()
+
+	describe.Reset()
+	pods, err := kube.GetPods("", "")
+	if err != nil {
+		describe.WriteString(err.Error() + "\n")
+	}
+	for _, p := range pods {
+		if p.Status != "Running" && p.Status != "Completed" {
+			describe.WriteString(kubectl("describe pod " + p.Name + " -n " + p.NameSpace))
+		}
+	}
+	files["describe-pods.log"] = describe.String()
+
+	return files
+}
+
+func bundleName(spec string) string {
+	name := strings.Trim(nameRegex.ReplaceAllString(strings.ToLower(spec), "-"), "-")
+	if len(name) > maxNameLength {
+		name = name[:maxNameLength]
+	}
+
+	return fmt.Sprintf("%s-%s.tar.gz", name, time.Now().Format("20060102-150405"))
+}
+
+// writeBundle writes the redacted files into a tar.gz on path.
+func writeBundle(path string, files map[string]string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return shared.ReturnLogError("failed to create diagnostics dir: %v", err)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return shared.ReturnLogError("failed to create diagnostics bundle: %v", err)
+	}
+	defer f.Close()
+
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	for name, content := range files {
+		content = shared.Redact(content)
+		header := &tar.Header{
+			Name:    name,
+			Mode:    0o644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}
+		if err = tw.WriteHeader(header); err != nil {
+			return shared.ReturnLogError("failed to write %s header: %v", name, err)
+		}
+		if _, err = tw.Write([]byte(content)); err != nil {
+			return shared.ReturnLogError("failed to write %s: %v", name, err)
+		}
+	}
+
+	if err = tw.Close(); err != nil {
+		return shared.ReturnLogError("failed to close tar: %v", err)
+	}
+
+	return gz.Close()
+}
@@ -0,0 +1,59 @@
+package kube
+
+import (
+	"sync"
+
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+var (
+	clientset     *kubernetes.Clientset
+	dynamicClient dynamic.Interface
+	clientErr     error
+	once          sync.Once
+)
+
+// Client returns a singleton clientset built from shared.KubeConfigFile.
+//
+// It should be called after the cluster is added, when the kubeconfig file is already set.
+func Client() (*kubernetes.Clientset, error) {
+	once.Do(newClients)
+
+	return clientset, clientErr
+}
+
+// DynamicClient returns a singleton dynamic client built from shared.KubeConfigFile,
+// used for custom resources like the system-upgrade-controller plans.
+func DynamicClient() (dynamic.Interface, error) {
+	once.Do(newClients)
+
+	return dynamicClient, clientErr
+}
+
+func newClients() {
+	if shared.KubeConfigFile == "" {
+		clientErr = shared.ReturnLogError("kubeconfig file is not set")
+		return
+	}
+
+	restConfig, err := clientcmd.BuildConfigFromFlags("", shared.KubeConfigFile)
+	if err != nil {
+		clientErr = shared.ReturnLogError("failed to build config from %s: %w", shared.KubeConfigFile, err)
+		return
+	}
+
+	clientset, err = kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		clientErr = shared.ReturnLogError("failed to create clientset: %w", err)
+		return
+	}
+
+	dynamicClient, err = dynamic.NewForConfig(restConfig)
+	if err != nil {
+		clientErr = shared.ReturnLogError("failed to create dynamic client: %w", err)
+	}
+}
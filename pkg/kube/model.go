@@ -0,0 +1,84 @@
+package kube
+
+// Node represents a cluster node with the same columns as kubectl get nodes -o wide
+// plus labels, taints and conditions.
+type Node struct {
+	Name        string
+	Status      string
+	Roles       string
+	Version     string
+	InternalIP  string
+	ExternalIP  string
+	InternalIPs []string
+	OSImage     string
+	Labels      map[string]string
+	Taints      []Taint
+	Conditions  []Condition
+}
+
+// Pod represents a pod with the same columns as kubectl get pods -o wide
+// plus labels, conditions and container statuses.
+type Pod struct {
+	NameSpace  string
+	Name       string
+	Ready      string
+	Status     string
+	Restarts   int
+	NodeIP     string
+	Node       string
+	PodIPs     []string
+	Labels     map[string]string
+	Conditions []Condition
+	Containers []ContainerStatus
+}
+
+// Service represents a service with its ips, ports and selector.
+type Service struct {
+	NameSpace      string
+	Name           string
+	Type           string
+	ClusterIP      string
+	ClusterIPs     []string
+	IPFamilies     []string
+	IPFamilyPolicy string
+	ExternalIPs    []string
+	Ports          []ServicePort
+	Labels         map[string]string
+	Selector       map[string]string
+}
+
+// Condition represents a node or pod condition.
+type Condition struct {
+	Type    string
+	Status  string
+	Reason  string
+	Message string
+}
+
+// Taint represents a node taint.
+type Taint struct {
+	Key    string
+	Value  string
+	Effect string
+}
+
+// ContainerStatus represents the status of a container or init container of a pod.
+type ContainerStatus struct {
+	Name     string
+	Image    string
+	Init     bool
+	Ready    bool
+	State    string
+	Reason   string
+	ExitCode int32
+	Restarts int
+}
+
+// ServicePort represents a service port.
+type ServicePort struct {
+	Name       string
+	Protocol   string
+	Port       int32
+	TargetPort string
+	NodePort   int32
+}
@@ -0,0 +1,144 @@
+package kube
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const roleLabelPrefix = "node-role.kubernetes.io/"
+
+// GetNodes returns all nodes of the cluster.
+func GetNodes() ([]Node, error) {
+	return GetNodesBySelector("")
+}
+
+// GetNodesBySelector returns the nodes that match the label selector.
+func GetNodesBySelector(selector string) ([]Node, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.ListOptions{LabelSelector: selector}
+	list, err := client.CoreV1().Nodes().List(context.TODO(), opts)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to list nodes: %w", err)
+	}
+
+	nodes := make([]Node, 0, len(list.Items))
+	for i := range list.Items {
+		nodes = append(nodes, newNode(&list.Items[i]))
+	}
+
+	return nodes, nil
+}
+
+func newNode(n *corev1.Node) Node {
+	node := Node{
+		Name:    n.Name,
+		Status:  nodeStatus(n),
+		Roles:   nodeRoles(n.Labels),
+		Version: n.Status.NodeInfo.KubeletVersion,
+		OSImage: n.Status.NodeInfo.OSImage,
+		Labels:  n.Labels,
+	}
+
+	for _, address := range n.Status.Addresses {
+		switch address.Type {
+		case corev1.NodeInternalIP:
+			if node.InternalIP == "" {
+				node.InternalIP = address.Address
+			}
+			node.InternalIPs = append(node.InternalIPs, address.Address)
+		case corev1.NodeExternalIP:
+			if node.ExternalIP == "" {
+				node.ExternalIP = address.Address
+			}
+		}
+	}
+
+	for _, t := range n.Spec.Taints {
+		node.Taints = append(node.Taints, Taint{Key: t.Key, Value: t.Value, Effect: string(t.Effect)})
+	}
+
+	for _, c := range n.Status.Conditions {
+		node.Conditions = append(node.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+
+	return node
+}
+
+// nodeStatus returns the node status the same way kubectl prints it.
+func nodeStatus(n *corev1.Node) string {
+	status := "Unknown"
+	for _, c := range n.Status.Conditions {
+		if c.Type != corev1.NodeReady {
+			continue
+		}
+		if c.Status == corev1.ConditionTrue {
+			status = "Ready"
+		} else {
+			status = "NotReady"
+		}
+	}
+
+	if n.Spec.Unschedulable {
+		status += ",SchedulingDisabled"
+	}
+
+	return status
+}
+
+// nodeRoles returns the roles from node-role.kubernetes.io labels comma separated.
+func nodeRoles(labels map[string]string) string {
+	var roles []string
+	for label := range labels {
+		if strings.HasPrefix(label, roleLabelPrefix) {
+			roles = append(roles, strings.TrimPrefix(label, roleLabelPrefix))
+		}
+	}
+
+	if len(roles) == 0 {
+		return "<none>"
+	}
+	sort.Strings(roles)
+
+	return strings.Join(roles, ",")
+}
+
+// HasTaint returns true when the node has a taint with the key.
+func (n Node) HasTaint(key string) bool {
+	for _, t := range n.Taints {
+		if t.Key == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Condition returns the node condition by type.
+func (n Node) Condition(conditionType string) (Condition, bool) {
+	return findCondition(n.Conditions, conditionType)
+}
+
+func findCondition(conditions []Condition, conditionType string) (Condition, bool) {
+	for _, c := range conditions {
+		if c.Type == conditionType {
+			return c, true
+		}
+	}
+
+	return Condition{}, false
+}
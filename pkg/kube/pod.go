@@ -0,0 +1,141 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+// GetPods returns the pods of the namespace that match the label selector.
+//
+// empty namespace returns pods from all namespaces and empty selector returns all pods.
+func GetPods(namespace, selector string) ([]Pod, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.ListOptions{LabelSelector: selector}
+	list, err := client.CoreV1().Pods(namespace).List(context.TODO(), opts)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to list pods: %w", err)
+	}
+
+	pods := make([]Pod, 0, len(list.Items))
+	for i := range list.Items {
+		pods = append(pods, newPod(&list.Items[i]))
+	}
+
+	return pods, nil
+}
+
+func newPod(p *corev1.Pod) Pod {
+	pod := Pod{
+		NameSpace: p.Namespace,
+		Name:      p.Name,
+		Status:    podStatus(p),
+		NodeIP:    p.Status.PodIP,
+		Node:      p.Spec.NodeName,
+		Labels:    p.Labels,
+	}
+
+	for _, ip := range p.Status.PodIPs {
+		pod.PodIPs = append(pod.PodIPs, ip.IP)
+	}
+
+	for _, c := range p.Status.Conditions {
+		pod.Conditions = append(pod.Conditions, Condition{
+			Type:    string(c.Type),
+			Status:  string(c.Status),
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+
+	ready := 0
+	for _, s := range p.Status.InitContainerStatuses {
+		pod.Containers = append(pod.Containers, newContainerStatus(s, true))
+	}
+	for _, s := range p.Status.ContainerStatuses {
+		pod.Containers = append(pod.Containers, newContainerStatus(s, false))
+		pod.Restarts += int(s.RestartCount)
+		if s.Ready {
+			ready++
+		}
+	}
+	pod.Ready = fmt.Sprintf("%d/%d", ready, len(p.Spec.Containers))
+
+	return pod
+}
+
+func newContainerStatus(s corev1.ContainerStatus, init bool) ContainerStatus {
+	status := ContainerStatus{
+		Name:     s.Name,
+		Image:    s.Image,
+		Init:     init,
+		Ready:    s.Ready,
+		Restarts: int(s.RestartCount),
+	}
+
+	switch {
+	case s.State.Running != nil:
+		status.State = "Running"
+	case s.State.Waiting != nil:
+		status.State = "Waiting"
+		status.Reason = s.State.Waiting.Reason
+	case s.State.Terminated != nil:
+		status.State = "Terminated"
+		status.Reason = s.State.Terminated.Reason
+		status.ExitCode = s.State.Terminated.ExitCode
+	}
+
+	return status
+}
+
+// podStatus returns the pod status the same way kubectl prints it.
+func podStatus(p *corev1.Pod) string {
+	status := string(p.Status.Phase)
+	if p.Status.Reason != "" {
+		status = p.Status.Reason
+	}
+
+	for i, s := range p.Status.InitContainerStatuses {
+		switch {
+		case s.State.Terminated != nil && s.State.Terminated.ExitCode == 0:
+			continue
+		case s.State.Terminated != nil:
+			if s.State.Terminated.Reason == "" {
+				return fmt.Sprintf("Init:ExitCode:%d", s.State.Terminated.ExitCode)
+			}
+			return "Init:" + s.State.Terminated.Reason
+		case s.State.Waiting != nil && s.State.Waiting.Reason != "" && s.State.Waiting.Reason != "PodInitializing":
+			return "Init:" + s.State.Waiting.Reason
+		default:
+			return fmt.Sprintf("Init:%d/%d", i, len(p.Spec.InitContainers))
+		}
+	}
+
+	for _, s := range p.Status.ContainerStatuses {
+		switch {
+		case s.State.Waiting != nil && s.State.Waiting.Reason != "":
+			status = s.State.Waiting.Reason
+		case s.State.Terminated != nil && s.State.Terminated.Reason != "":
+			status = s.State.Terminated.Reason
+		}
+	}
+
+	if p.DeletionTimestamp != nil {
+		status = "Terminating"
+	}
+
+	return status
+}
+
+// Condition returns the pod condition by type.
+func (p Pod) Condition(conditionType string) (Condition, bool) {
+	return findCondition(p.Conditions, conditionType)
+}
@@ -0,0 +1,88 @@
+package kube
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+// GetServices returns the services of the namespace that match the label selector.
+//
+// empty namespace returns services from all namespaces and empty selector returns all services.
+func GetServices(namespace, selector string) ([]Service, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := metav1.ListOptions{LabelSelector: selector}
+	list, err := client.CoreV1().Services(namespace).List(context.TODO(), opts)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to list services: %w", err)
+	}
+
+	services := make([]Service, 0, len(list.Items))
+	for i := range list.Items {
+		services = append(services, newService(&list.Items[i]))
+	}
+
+	return services, nil
+}
+
+// GetService returns the service by namespace and name.
+func GetService(namespace, name string) (*Service, error) {
+	client, err := Client()
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := client.CoreV1().Services(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to get service %s/%s: %w", namespace, name, err)
+	}
+
+	service := newService(svc)
+
+	return &service, nil
+}
+
+func newService(s *corev1.Service) Service {
+	service := Service{
+		NameSpace:   s.Namespace,
+		Name:        s.Name,
+		Type:        string(s.Spec.Type),
+		ClusterIP:   s.Spec.ClusterIP,
+		ClusterIPs:  s.Spec.ClusterIPs,
+		ExternalIPs: s.Spec.ExternalIPs,
+		Labels:      s.Labels,
+		Selector:    s.Spec.Selector,
+	}
+
+	for _, family := range s.Spec.IPFamilies {
+		service.IPFamilies = append(service.IPFamilies, string(family))
+	}
+	if s.Spec.IPFamilyPolicy != nil {
+		service.IPFamilyPolicy = string(*s.Spec.IPFamilyPolicy)
+	}
+
+	for _, ingress := range s.Status.LoadBalancer.Ingress {
+		if ingress.IP != "" {
+			service.ExternalIPs = append(service.ExternalIPs, ingress.IP)
+		}
+	}
+
+	for _, p := range s.Spec.Ports {
+		service.Ports = append(service.Ports, ServicePort{
+			Name:       p.Name,
+			Protocol:   string(p.Protocol),
+			Port:       p.Port,
+			TargetPort: p.TargetPort.String(),
+			NodePort:   p.NodePort,
+		})
+	}
+
+	return service
+}
@@ -0,0 +1,61 @@
+package report
+
+import "time"
+
+// SuiteReport represents the machine readable report of an entrypoint suite run.
+type SuiteReport struct {
+	Suite      string            `json:"suite"`
+	Product    string            `json:"product"`
+	Version    string            `json:"version"`
+	Passed     bool              `json:"passed"`
+	StartTime  time.Time         `json:"start_time"`
+	Duration   time.Duration     `json:"duration"`
+	Topology   *Topology         `json:"topology,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Specs      []SpecReport      `json:"specs"`
+}
+
+// Topology represents the cluster nodes the suite ran against.
+type Topology struct {
+	Servers       []string `json:"servers"`
+	Agents        []string `json:"agents"`
+	WindowsAgents []string `json:"windows_agents,omitempty"`
+	DataStore     string   `json:"datastore,omitempty"`
+	Arch          string   `json:"arch,omitempty"`
+}
+
+// SpecReport represents a spec result with the commands run on each node while running it.
+type SpecReport struct {
+	Name     string               `json:"name"`
+	State    string               `json:"state"`
+	Duration time.Duration        `json:"duration"`
+	Failure  string               `json:"failure,omitempty"`
+	Location string               `json:"location,omitempty"`
+	Entries  map[string]string    `json:"entries,omitempty"`
+	Commands map[string][]Command `json:"commands,omitempty"`
+}
+
+// MatrixReport represents the results of a suite run once per value of a matrix dimension.
+type MatrixReport struct {
+	Suite     string      `json:"suite"`
+	Dimension string      `json:"dimension"`
+	Rows      []MatrixRow `json:"rows"`
+}
+
+// MatrixRow represents the suite run on a matrix value with the state of each spec.
+type MatrixRow struct {
+	Value    string            `json:"value"`
+	Version  string            `json:"version"`
+	Passed   bool              `json:"passed"`
+	Duration time.Duration     `json:"duration"`
+	Specs    map[string]string `json:"specs"`
+}
+
+// Command represents a command run on a node or on the host.
+type Command struct {
+	Cmd      string        `json:"cmd"`
+	ExitCode int           `json:"exit_code"`
+	Duration time.Duration `json:"duration"`
+	Stdout   string        `json:"stdout,omitempty"`
+	Stderr   string        `json:"stderr,omitempty"`
+}
@@ -0,0 +1,369 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+var (
+	mu         sync.Mutex
+	properties = map[string]string{}
+	specs      []SpecReport
+	version    string
+	matrix     struct{ dimension, value, run string }
+)
+
+var fileNameRegex = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// AddProperty attaches a property to the suite reports, as a junit property and on the json report.
+func AddProperty(name, value string) {
+	if value == "" {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	properties[name] = value
+}
+
+// SetMatrix marks the suite run as the value of a matrix dimension, as the datastore the cluster runs on.
+//
+// The report file names get the value as suffix and Write merges the reports of every value
+// found in the artifact dir of the same ENV_MATRIX_RUN into the matrix reports, only this one when not set.
+func SetMatrix(dimension, value string) {
+	run := os.Getenv("ENV_MATRIX_RUN")
+	if run == "" {
+		run = strconv.FormatInt(time.Now().UnixNano(), 10)
+	}
+	AddProperty(dimension, value)
+	AddProperty("matrix_run", run)
+
+	mu.Lock()
+	defer mu.Unlock()
+	matrix.dimension, matrix.value, matrix.run = dimension, value, run
+}
+
+// AddSpec records the spec result with the commands run since the previous spec.
+//
+// It should be called from ReportAfterEach, while the cluster is still available.
+func AddSpec(r types.SpecReport) {
+	spec := SpecReport{
+		Name:     r.FullText(),
+		State:    r.State.String(),
+		Duration: r.RunTime,
+		Entries:  map[string]string{},
+		Commands: map[string][]Command{},
+	}
+
+	if r.Failed() {
+		spec.Failure = r.Failure.Message
+		spec.Location = r.Failure.Location.String()
+	}
+
+	for _, entry := range r.ReportEntries {
+		spec.Entries[entry.Name] = entry.StringRepresentation()
+	}
+
+	for _, res := range shared.TakeTranscript() {
+		node := res.NodeIP
+		if node == "" {
+			node = "host"
+		}
+		spec.Commands[node] = append(spec.Commands[node], Command{
+			Cmd:      res.Cmd,
+			ExitCode: res.ExitCode,
+			Duration: res.Duration,
+			Stdout:   res.Stdout,
+			Stderr:   res.Stderr,
+		})
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	specs = append(specs, spec)
+}
+
+// AddVersion records the product version running on the cluster for the suite reports.
+//
+// It should be called from AfterSuite, before the cluster is destroyed.
+func AddVersion() {
+	if factory.GetCluster() == nil {
+		return
+	}
+
+	product, err := shared.GetProduct()
+	if err != nil {
+		return
+	}
+	v, err := shared.GetProductVersion(product)
+	// the version lookup is not part of any spec.
+	shared.TakeTranscript()
+	if err != nil {
+		return
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	version = strings.TrimSpace(v)
+}
+
+// Write writes the junit xml and json reports of the suite into the artifact dir.
+//
+// It should be called from ReportAfterSuite.
+func Write(r types.Report) error {
+	dir, err := shared.ArtifactDir()
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	suite := fileName(r)
+	name := suite
+	if matrix.value != "" {
+		name += "-" + strings.Trim(fileNameRegex.ReplaceAllString(strings.ToLower(matrix.value), "-"), "-")
+	}
+	junitPath := filepath.Join(dir, name+".xml")
+	jsonPath := filepath.Join(dir, name+".json")
+
+	if err = writeJUnit(r, junitPath); err != nil {
+		return err
+	}
+
+	if err = writeJSON(r, jsonPath); err != nil {
+		return err
+	}
+
+	shared.LogLevel("info", "reports written to %s and %s", junitPath, jsonPath)
+
+	if matrix.value == "" {
+		return nil
+	}
+
+	return writeMatrix(dir, suite)
+}
+
+// writeMatrix merges the json reports of each matrix value of the suite run into a json and a markdown
+// report, with the result of every spec per value. Reports left from other runs are skipped.
+func writeMatrix(dir, name string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, name+"-*.json"))
+	if err != nil {
+		return shared.ReturnLogError("failed to list %s reports: %w", name, err)
+	}
+
+	report := MatrixReport{Dimension: matrix.dimension}
+	for _, path := range paths {
+		if path == filepath.Join(dir, name+"-matrix.json") {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return shared.ReturnLogError("failed to read report %s: %w", path, err)
+		}
+
+		var suite SuiteReport
+		if err = json.Unmarshal(content, &suite); err != nil {
+			return shared.ReturnLogError("failed to parse report %s: %w", path, err)
+		}
+
+		value, ok := suite.Properties[matrix.dimension]
+		if !ok || suite.Properties["matrix_run"] != matrix.run {
+			continue
+		}
+		report.Suite = suite.Suite
+
+		row := MatrixRow{
+			Value:    value,
+			Version:  suite.Version,
+			Passed:   suite.Passed,
+			Duration: suite.Duration,
+			Specs:    map[string]string{},
+		}
+		for _, spec := range suite.Specs {
+			row.Specs[spec.Name] = spec.State
+		}
+		report.Rows = append(report.Rows, row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool { return report.Rows[i].Value < report.Rows[j].Value })
+
+	content, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return shared.ReturnLogError("failed to encode matrix report: %w", err)
+	}
+
+	jsonPath := filepath.Join(dir, name+"-matrix.json")
+	if err = os.WriteFile(jsonPath, content, 0o644); err != nil {
+		return shared.ReturnLogError("failed to write matrix report: %w", err)
+	}
+
+	mdPath := filepath.Join(dir, name+"-matrix.md")
+	if err = os.WriteFile(mdPath, []byte(matrixMarkdown(report)), 0o644); err != nil {
+		return shared.ReturnLogError("failed to write matrix report: %w", err)
+	}
+
+	shared.LogLevel("info", "matrix reports written to %s and %s", jsonPath, mdPath)
+
+	return nil
+}
+
+// matrixMarkdown returns the matrix as a markdown table with a column per value and a row per spec.
+func matrixMarkdown(report MatrixReport) string {
+	var names []string
+	seen := map[string]bool{}
+	for _, row := range report.Rows {
+		for name := range row.Specs {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString("# " + report.Suite + "\n\n| " + report.Dimension + " |")
+	separator := "|---|"
+	for _, row := range report.Rows {
+		b.WriteString(" " + row.Value + " |")
+		separator += "---|"
+	}
+	b.WriteString("\n" + separator + "\n| version |")
+	for _, row := range report.Rows {
+		b.WriteString(" " + row.Version + " |")
+	}
+	b.WriteString("\n| suite |")
+	for _, row := range report.Rows {
+		result := "failed"
+		if row.Passed {
+			result = "passed"
+		}
+		b.WriteString(" " + result + " |")
+	}
+
+	for _, name := range names {
+		b.WriteString("\n| " + name + " |")
+		for _, row := range report.Rows {
+			state := row.Specs[name]
+			if state == "" {
+				state = "-"
+			}
+			b.WriteString(" " + state + " |")
+		}
+	}
+	b.WriteString("\n")
+
+	return b.String()
+}
+
+// writeJUnit generates the ginkgo junit report and attaches the properties to the test suites.
+func writeJUnit(r types.Report, path string) error {
+	if err := reporters.GenerateJUnitReport(r, path); err != nil {
+		return shared.ReturnLogError("failed to generate junit report: %w", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return shared.ReturnLogError("failed to read junit report: %w", err)
+	}
+
+	suites := reporters.JUnitTestSuites{}
+	if err = xml.Unmarshal(content, &suites); err != nil {
+		return shared.ReturnLogError("failed to parse junit report: %w", err)
+	}
+
+	props := suiteProperties()
+	for i := range suites.TestSuites {
+		for _, name := range sortedKeys(props) {
+			suites.TestSuites[i].Properties.Properties = append(suites.TestSuites[i].Properties.Properties,
+				reporters.JUnitProperty{Name: name, Value: props[name]})
+		}
+	}
+
+	content, err = xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return shared.ReturnLogError("failed to encode junit report: %w", err)
+	}
+
+	return os.WriteFile(path, append([]byte(xml.Header), content...), 0o644)
+}
+
+func writeJSON(r types.Report, path string) error {
+	suite := SuiteReport{
+		Suite:      r.SuiteDescription,
+		Version:    version,
+		Passed:     r.SuiteSucceeded,
+		StartTime:  r.StartTime,
+		Duration:   r.RunTime,
+		Properties: suiteProperties(),
+		Specs:      specs,
+	}
+
+	if product, err := shared.GetProduct(); err == nil {
+		suite.Product = product
+	}
+
+	if c := factory.GetCluster(); c != nil {
+		suite.Topology = &Topology{
+			Servers:       c.ServerIPs,
+			Agents:        c.AgentIPs,
+			WindowsAgents: c.WinAgentIPs,
+			DataStore:     c.Config.Backend(),
+			Arch:          c.Config.Arch,
+		}
+	}
+
+	content, err := json.MarshalIndent(suite, "", "  ")
+	if err != nil {
+		return shared.ReturnLogError("failed to encode json report: %w", err)
+	}
+
+	return os.WriteFile(path, content, 0o644)
+}
+
+// suiteProperties returns the properties added plus the product version under test.
+func suiteProperties() map[string]string {
+	props := make(map[string]string, len(properties)+1)
+	for k, v := range properties {
+		props[k] = v
+	}
+	if version != "" {
+		props["version"] = version
+	}
+
+	return props
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// fileName returns the suite package name, since different suites can share the same description.
+func fileName(r types.Report) string {
+	name := filepath.Base(r.SuitePath)
+	if name == "." || name == "/" {
+		name = r.SuiteDescription
+	}
+
+	return strings.Trim(fileNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
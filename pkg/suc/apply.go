@@ -0,0 +1,109 @@
+package suc
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+var planResource = schema.GroupVersionResource{Group: "upgrade.cattle.io", Version: "v1", Resource: "plans"}
+
+// ApplyPlans creates or updates the plans on the cluster.
+//
+// It retries while the Plan CRD is not registered yet, since the controller registers it on startup.
+func ApplyPlans(plans []Plan) error {
+	client, err := kube.DynamicClient()
+	if err != nil {
+		return err
+	}
+
+	for i := range plans {
+		obj, err := toUnstructured(&plans[i])
+		if err != nil {
+			return err
+		}
+
+		deadline := time.Now().Add(2 * time.Minute)
+		for {
+			err = applyPlan(client.Resource(planResource).Namespace(Namespace), obj)
+			if err == nil || !apierrors.IsNotFound(err) || time.Now().After(deadline) {
+				break
+			}
+			time.Sleep(5 * time.Second)
+		}
+		if err != nil {
+			return shared.ReturnLogError("failed to apply plan %s: %w", plans[i].Metadata.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// LatestVersion returns the version the controller resolved the channel of the product server plan to,
+// from the plan status.
+func LatestVersion(product string) (string, error) {
+	client, err := kube.DynamicClient()
+	if err != nil {
+		return "", err
+	}
+
+	name := product + "-server-cp"
+	plans := client.Resource(planResource).Namespace(Namespace)
+	plan, err := plans.Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", shared.ReturnLogError("failed to get plan %s: %w", name, err)
+	}
+
+	version, _, err := unstructured.NestedString(plan.Object, "status", "latestVersion")
+	if err != nil || version == "" {
+		return "", shared.ReturnLogError("plan %s has no latest version resolved: %v", name, err)
+	}
+
+	return version, nil
+}
+
+type resourceClient interface {
+	Get(ctx context.Context, name string, opts metav1.GetOptions, sub ...string) (*unstructured.Unstructured, error)
+	Create(ctx context.Context, obj *unstructured.Unstructured, opts metav1.CreateOptions,
+		sub ...string) (*unstructured.Unstructured, error)
+	Update(ctx context.Context, obj *unstructured.Unstructured, opts metav1.UpdateOptions,
+		sub ...string) (*unstructured.Unstructured, error)
+}
+
+func applyPlan(client resourceClient, obj *unstructured.Unstructured) error {
+	current, err := client.Get(context.TODO(), obj.GetName(), metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		_, err = client.Create(context.TODO(), obj, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	obj.SetResourceVersion(current.GetResourceVersion())
+	_, err = client.Update(context.TODO(), obj, metav1.UpdateOptions{})
+
+	return err
+}
+
+func toUnstructured(plan *Plan) (*unstructured.Unstructured, error) {
+	data, err := json.Marshal(plan)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to marshal plan %s: %w", plan.Metadata.Name, err)
+	}
+
+	obj := &unstructured.Unstructured{}
+	if err = obj.UnmarshalJSON(data); err != nil {
+		return nil, shared.ReturnLogError("failed to convert plan %s: %w", plan.Metadata.Name, err)
+	}
+
+	return obj, nil
+}
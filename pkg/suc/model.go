@@ -0,0 +1,64 @@
+package suc
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Plan is the subset of the system-upgrade-controller upgrade.cattle.io/v1 Plan used by the tests.
+type Plan struct {
+	APIVersion string            `json:"apiVersion"`
+	Kind       string            `json:"kind"`
+	Metadata   metav1.ObjectMeta `json:"metadata"`
+	Spec       PlanSpec          `json:"spec"`
+}
+
+type PlanSpec struct {
+	Concurrency        int64                 `json:"concurrency"`
+	Version            string                `json:"version,omitempty"`
+	Channel            string                `json:"channel,omitempty"`
+	NodeSelector       *metav1.LabelSelector `json:"nodeSelector,omitempty"`
+	Tolerations        []corev1.Toleration   `json:"tolerations,omitempty"`
+	ServiceAccountName string                `json:"serviceAccountName"`
+	Prepare            *ContainerSpec        `json:"prepare,omitempty"`
+	Cordon             bool                  `json:"cordon,omitempty"`
+	Drain              *DrainSpec            `json:"drain,omitempty"`
+	Upgrade            *ContainerSpec        `json:"upgrade"`
+}
+
+type ContainerSpec struct {
+	Image string   `json:"image"`
+	Args  []string `json:"args,omitempty"`
+}
+
+type DrainSpec struct {
+	Force            bool   `json:"force,omitempty"`
+	IgnoreDaemonSets *bool  `json:"ignoreDaemonSets,omitempty"`
+	DeleteLocalData  *bool  `json:"deleteLocalData,omitempty"`
+	GracePeriod      *int32 `json:"gracePeriod,omitempty"`
+}
+
+// PlanConfig holds the parameters the server and agent plans are built from.
+type PlanConfig struct {
+	Product string
+	// Version to upgrade to, ignored when Channel is set.
+	Version string
+	// Channel is a release channel URL, e.g. https://update.k3s.io/v1-release/channels/stable.
+	Channel            string
+	ServerConcurrency  int64
+	AgentConcurrency   int64
+	CordonServers      bool
+	DrainServers       *DrainSpec
+	DrainAgents        *DrainSpec
+	ServerTolerations  []corev1.Toleration
+	AgentTolerations   []corev1.Toleration
+	ServiceAccountName string
+}
+
+// NodeProgress is the state of a plan job on a node.
+type NodeProgress struct {
+	Plan   string
+	Node   string
+	Job    string
+	Status string
+}
@@ -0,0 +1,108 @@
+package suc
+
+import (
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	Namespace = "system-upgrade"
+
+	etcdRoleLabel         = "node-role.kubernetes.io/etcd"
+	controlPlaneRoleLabel = "node-role.kubernetes.io/control-plane"
+)
+
+// DefaultPlanConfig returns the plan parameters the upgrade suites use:
+// servers one at a time cordoned, agents two at a time drained.
+func DefaultPlanConfig(product, versionOrChannel string) PlanConfig {
+	cfg := PlanConfig{
+		Product:            product,
+		ServerConcurrency:  1,
+		AgentConcurrency:   2,
+		CordonServers:      true,
+		DrainServers:       &DrainSpec{Force: true},
+		DrainAgents:        &DrainSpec{Force: true},
+		ServerTolerations:  []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+		ServiceAccountName: Namespace,
+	}
+
+	if strings.HasPrefix(versionOrChannel, "http://") || strings.HasPrefix(versionOrChannel, "https://") {
+		cfg.Channel = versionOrChannel
+	} else {
+		cfg.Version = versionOrChannel
+	}
+
+	return cfg
+}
+
+// NewPlans builds the control-plane, etcd and agent plans, in the order they are upgraded.
+// Each plan waits on the previous one through its prepare step.
+func NewPlans(cfg PlanConfig) []Plan {
+	image := "rancher/" + cfg.Product + "-upgrade"
+	cpName := cfg.Product + "-server-cp"
+	etcdName := cfg.Product + "-server-etcd"
+
+	controlPlane := newPlan(cfg, cpName, "server")
+	controlPlane.Spec.Concurrency = cfg.ServerConcurrency
+	controlPlane.Spec.NodeSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: controlPlaneRoleLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"true"}},
+		},
+	}
+	controlPlane.Spec.Tolerations = cfg.ServerTolerations
+	controlPlane.Spec.Cordon = cfg.CordonServers
+
+	etcd := newPlan(cfg, etcdName, "server")
+	etcd.Spec.Concurrency = cfg.ServerConcurrency
+	etcd.Spec.NodeSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: "rke.cattle.io/etcd-role", Operator: metav1.LabelSelectorOpExists},
+			{Key: etcdRoleLabel, Operator: metav1.LabelSelectorOpIn, Values: []string{"true"}},
+			{Key: controlPlaneRoleLabel, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"}},
+		},
+	}
+	etcd.Spec.Tolerations = cfg.ServerTolerations
+	etcd.Spec.Cordon = cfg.CordonServers
+	etcd.Spec.Drain = cfg.DrainServers
+	etcd.Spec.Prepare = &ContainerSpec{Image: image, Args: []string{"prepare", cpName}}
+
+	agent := newPlan(cfg, cfg.Product+"-agent", "agent")
+	agent.Spec.Concurrency = cfg.AgentConcurrency
+	agent.Spec.NodeSelector = &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{Key: etcdRoleLabel, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"}},
+			{Key: controlPlaneRoleLabel, Operator: metav1.LabelSelectorOpNotIn, Values: []string{"true"}},
+		},
+	}
+	agent.Spec.Tolerations = cfg.AgentTolerations
+	agent.Spec.Drain = cfg.DrainAgents
+	agent.Spec.Prepare = &ContainerSpec{Image: image, Args: []string{"prepare", etcdName}}
+
+	return []Plan{controlPlane, etcd, agent}
+}
+
+func newPlan(cfg PlanConfig, name, nodeType string) Plan {
+	plan := Plan{
+		APIVersion: "upgrade.cattle.io/v1",
+		Kind:       "Plan",
+		Metadata: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: Namespace,
+			Labels:    map[string]string{cfg.Product + "-upgrade": nodeType},
+		},
+		Spec: PlanSpec{
+			ServiceAccountName: cfg.ServiceAccountName,
+			Upgrade:            &ContainerSpec{Image: "rancher/" + cfg.Product + "-upgrade"},
+		},
+	}
+
+	if cfg.Channel != "" {
+		plan.Spec.Channel = cfg.Channel
+	} else {
+		plan.Spec.Version = cfg.Version
+	}
+
+	return plan
+}
@@ -0,0 +1,162 @@
+package suc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const (
+	planLabel = "upgrade.cattle.io/plan"
+	nodeLabel = "upgrade.cattle.io/node"
+
+	statusPending  = "Pending"
+	statusRunning  = "Running"
+	statusComplete = "Complete"
+	statusFailed   = "Failed"
+)
+
+// WatchPlans reports the progress of the plan jobs created after since on each node the plans select,
+// until all of them complete, one fails or the timeout expires.
+func WatchPlans(plans []Plan, since time.Time, timeout time.Duration) error {
+	reported := map[string]string{}
+	deadline := time.Now().Add(timeout)
+
+	for {
+		progress, err := planProgress(plans, since)
+		if err != nil {
+			return err
+		}
+
+		done := true
+		var failed []string
+		for _, p := range progress {
+			key := p.Plan + "/" + p.Node
+			if reported[key] != p.Status {
+				reported[key] = p.Status
+				fmt.Printf("Plan %s on node %s: %s %s\n", p.Plan, p.Node, p.Status, p.Job)
+			}
+
+			switch p.Status {
+			case statusFailed:
+				failed = append(failed, fmt.Sprintf("plan %s node %s job %s", p.Plan, p.Node, p.Job))
+			case statusComplete:
+			default:
+				done = false
+			}
+		}
+
+		if len(failed) > 0 {
+			return shared.ReturnLogError("upgrade plan jobs failed:\n%s", strings.Join(failed, "\n"))
+		}
+		if done {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return shared.ReturnLogError("upgrade plans did not complete after %s:\n%s",
+				timeout, describePending(progress))
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// planProgress returns the state of the latest job of each plan on each node it selects.
+func planProgress(plans []Plan, since time.Time) ([]NodeProgress, error) {
+	client, err := kube.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	var progress []NodeProgress
+	for i := range plans {
+		plan := &plans[i]
+
+		selector, err := metav1.LabelSelectorAsSelector(plan.Spec.NodeSelector)
+		if err != nil {
+			return nil, shared.ReturnLogError("invalid node selector on plan %s: %w", plan.Metadata.Name, err)
+		}
+
+		nodes, err := kube.GetNodesBySelector(selector.String())
+		if err != nil {
+			return nil, err
+		}
+
+		opts := metav1.ListOptions{LabelSelector: planLabel + "=" + plan.Metadata.Name}
+		jobs, err := client.BatchV1().Jobs(Namespace).List(context.TODO(), opts)
+		if err != nil {
+			return nil, shared.ReturnLogError("failed to list jobs of plan %s: %w", plan.Metadata.Name, err)
+		}
+
+		latest := latestJobs(jobs.Items, since)
+		for _, n := range nodes {
+			p := NodeProgress{Plan: plan.Metadata.Name, Node: n.Name, Status: statusPending}
+			if job, ok := latest[n.Name]; ok {
+				p.Job = job.Name
+				p.Status = jobStatus(job)
+			}
+			progress = append(progress, p)
+		}
+	}
+
+	return progress, nil
+}
+
+// latestJobs returns the newest job per node created after since,
+// skipping the ones left over from previous upgrades.
+func latestJobs(jobs []batchv1.Job, since time.Time) map[string]*batchv1.Job {
+	latest := map[string]*batchv1.Job{}
+	for i := range jobs {
+		job := &jobs[i]
+		if job.CreationTimestamp.Time.Before(since) {
+			continue
+		}
+
+		node := job.Labels[nodeLabel]
+		if current, ok := latest[node]; !ok || current.CreationTimestamp.Before(&job.CreationTimestamp) {
+			latest[node] = job
+		}
+	}
+
+	return latest
+}
+
+func jobStatus(job *batchv1.Job) string {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return statusComplete
+		case batchv1.JobFailed:
+			return statusFailed
+		}
+	}
+
+	if job.Status.Succeeded > 0 {
+		return statusComplete
+	}
+
+	return statusRunning
+}
+
+func describePending(progress []NodeProgress) string {
+	var pending []string
+	for _, p := range progress {
+		if p.Status != statusComplete {
+			pending = append(pending, fmt.Sprintf("plan %s node %s: %s", p.Plan, p.Node, p.Status))
+		}
+	}
+
+	return strings.Join(pending, "\n")
+}
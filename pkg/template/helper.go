@@ -30,15 +30,25 @@ func updateExpectedValue(template VersionTestTemplate) {
 
 // executeTestCombination get a template and pass it to `processTestCombination` to execute test combination on group of IPs
 func executeTestCombination(v VersionTestTemplate) error {
-	ips := shared.FetchNodeExternalIP()
+	targets := make([][]string, len(v.TestCombination.Run))
+	size := 0
+	for i, testMap := range v.TestCombination.Run {
+		ips, err := targetIPs(testMap.Target)
+		if err != nil {
+			return err
+		}
+		if len(ips) == 0 {
+			return shared.ReturnLogError("no nodes found for target: %s", testMap.Target)
+		}
+
+		targets[i] = ips
+		size += len(ips) * len(strings.Split(testMap.Cmd, ","))
+	}
 
 	var wg sync.WaitGroup
-	errorChanList := make(
-		chan error,
-		len(ips)*(len(v.TestCombination.Run)),
-	)
+	errorChanList := make(chan error, size)
 
-	processTestCombination(errorChanList, &wg, ips, *v.TestCombination)
+	processTestCombination(errorChanList, &wg, targets, *v.TestCombination)
 
 	wg.Wait()
 	close(errorChanList)
@@ -70,6 +80,12 @@ func AddTestCases(names []string) ([]testCase, error) {
 		"TestServiceLoadBalancer":          testcase.TestServiceLoadBalancer,
 		"TestInternodeConnectivityMixedOS": testcase.TestInternodeConnectivityMixedOS,
 		"TestSonobuoyMixedOS":              testcase.TestSonobuoyMixedOS,
+		"TestEtcdSnapshotRestore":          testcase.TestEtcdSnapshotRestore,
+		"TestRegistryMirror":               testcase.TestRegistryMirror,
+		"TestCertRotation":                 testcase.TestCertRotation,
+		"TestSecretsEncryption":            testcase.TestSecretsEncryption,
+		"TestNetworkPolicy":                testcase.TestNetworkPolicy,
+		"TestPodSecurityAdmission":         testcase.TestPodSecurityAdmission,
 	}
 
 	for _, name := range names {
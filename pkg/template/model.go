@@ -22,6 +22,7 @@ type RunCmd struct {
 // TestMap represents a single test command with key:value pairs.
 type TestMap struct {
 	Cmd                  string
+	Target               string
 	ExpectedValue        string
 	ExpectedValueUpgrade string
 }
@@ -0,0 +1,141 @@
+package template
+
+import (
+	"bytes"
+	"os"
+
+	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/shared"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan represents a declarative version bump test loaded from a yaml file.
+type Plan struct {
+	Component      string        `yaml:"component"`
+	Description    string        `yaml:"description"`
+	Commands       []PlanCommand `yaml:"commands"`
+	TestCases      []string      `yaml:"test_cases"`
+	DeployWorkload bool          `yaml:"deploy_workload"`
+	WorkloadName   string        `yaml:"workload_name"`
+}
+
+// PlanCommand represents the commands per product and the expected values before and after upgrade.
+type PlanCommand struct {
+	Cmd                  map[string]string `yaml:"cmd"`
+	Target               string            `yaml:"target"`
+	ExpectedValue        string            `yaml:"expected_value"`
+	ExpectedValueUpgrade string            `yaml:"expected_value_upgrade"`
+}
+
+// LoadPlan reads and parses the yaml plan file.
+func LoadPlan(path string) (*Plan, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to read plan file: %w", err)
+	}
+
+	plan := &Plan{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(plan); err != nil {
+		return nil, shared.ReturnLogError("failed to parse plan file %s: %w", path, err)
+	}
+
+	if plan.Component == "" || len(plan.Commands) == 0 {
+		return nil, shared.ReturnLogError("plan %s must have a component and at least one command", path)
+	}
+
+	if _, err = AddTestCases(plan.TestCases); err != nil {
+		return nil, shared.ReturnLogError("plan %s has an invalid test case: %w", path, err)
+	}
+
+	return plan, nil
+}
+
+// Template converts the plan into a VersionTestTemplate for the product.
+//
+// Expected values, test cases and workload passed in as flags take precedence over the plan values.
+func (p *Plan) Template(product string) (*VersionTestTemplate, error) {
+	runs := make([]TestMap, 0, len(p.Commands))
+	for _, c := range p.Commands {
+		cmd, ok := c.Cmd[product]
+		if !ok {
+			return nil, shared.ReturnLogError("plan %s has no command for product: %s", p.Component, product)
+		}
+
+		target := c.Target
+		if TestMapTemplate.Target != "" {
+			target = TestMapTemplate.Target
+		}
+
+		testMap := TestMap{
+			Cmd:                  cmd,
+			Target:               target,
+			ExpectedValue:        c.ExpectedValue,
+			ExpectedValueUpgrade: c.ExpectedValueUpgrade,
+		}
+		if TestMapTemplate.ExpectedValue != "" {
+			testMap.ExpectedValue = TestMapTemplate.ExpectedValue
+		}
+		if TestMapTemplate.ExpectedValueUpgrade != "" {
+			testMap.ExpectedValueUpgrade = TestMapTemplate.ExpectedValueUpgrade
+		}
+
+		if testMap.ExpectedValue == "" {
+			return nil, shared.ReturnLogError("plan %s has no expected value for: %s", p.Component, cmd)
+		}
+		if customflag.ServiceFlag.InstallMode.String() != "" && testMap.ExpectedValueUpgrade == "" {
+			return nil, shared.ReturnLogError("plan %s has no expected value after upgrade for: %s",
+				p.Component, cmd)
+		}
+
+		runs = append(runs, testMap)
+	}
+
+	testConfig := p.testConfig()
+
+	description := p.Description
+	if customflag.ServiceFlag.TestConfig.Description != "" {
+		description = customflag.ServiceFlag.TestConfig.Description
+	}
+
+	return &VersionTestTemplate{
+		TestCombination: &RunCmd{Run: runs},
+		InstallMode:     customflag.ServiceFlag.InstallMode.String(),
+		TestConfig:      testConfig,
+		Description:     description,
+	}, nil
+}
+
+// testConfig returns the test cases and workload from flags or from the plan.
+//
+// The plan test cases are not part of it, they run as their own specs after the version bump.
+func (p *Plan) testConfig() *TestConfig {
+	flagConfig := customflag.ServiceFlag.TestConfig
+	testConfig := &TestConfig{
+		TestFunc:       ConvertToTestCase(flagConfig.TestFuncs),
+		DeployWorkload: p.Workload(),
+		WorkloadName:   flagConfig.WorkloadName,
+	}
+
+	if testConfig.WorkloadName == "" {
+		testConfig.WorkloadName = p.WorkloadName
+	}
+
+	return testConfig
+}
+
+// Workload reports whether the test cases deploy their workload, from the flag or from the plan.
+func (p *Plan) Workload() bool {
+	return customflag.ServiceFlag.TestConfig.DeployWorkload || p.DeployWorkload
+}
+
+// SpecTestCases returns the plan test cases to run as specs after the version bump,
+// none when test cases are passed in as flags as those run within the version bump.
+func (p *Plan) SpecTestCases() []string {
+	if len(customflag.ServiceFlag.TestConfig.TestFuncs) > 0 {
+		return nil
+	}
+
+	return p.TestCases
+}
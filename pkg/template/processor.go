@@ -36,7 +36,7 @@ func processCmds(resultChan chan error, wg *sync.WaitGroup, ip string, cmds []st
 			defer wg.Done()
 			defer GinkgoRecover()
 
-			if strings.Contains(cmd, "kubectl") || strings.HasPrefix(cmd, "helm") {
+			if ip == hostIP || strings.Contains(cmd, "kubectl") || strings.HasPrefix(cmd, "helm") {
 				processOnHost(resultChan, ip, cmd, expectedValue)
 			} else {
 				processOnNode(resultChan, ip, cmd, expectedValue)
@@ -45,35 +45,19 @@ func processCmds(resultChan chan error, wg *sync.WaitGroup, ip string, cmds []st
 	}
 }
 
-func processTestCombination(resultChan chan error, wg *sync.WaitGroup, ips []string, testCombination RunCmd) {
+// processTestCombination runs each testMap commands on the node IPs resolved from its target.
+func processTestCombination(
+	resultChan chan error,
+	wg *sync.WaitGroup,
+	targets [][]string,
+	testCombination RunCmd,
+) {
 	if testCombination.Run != nil {
-		for _, testMap := range testCombination.Run {
+		for i, testMap := range testCombination.Run {
 			cmds := strings.Split(testMap.Cmd, ",")
-			expectedValues := strings.Split(testMap.ExpectedValue, ",")
-
-			if strings.Contains(testMap.Cmd, "etcd") {
-				cmdToGetIps := fmt.Sprintf(`
-				kubectl get node -A -o wide --kubeconfig="%s" \
-				| grep 'etcd' | awk '{print $7}'
-				`, shared.KubeConfigFile)
-
-				var nodes []string
-				nodeIps, err := shared.RunCommandHost(cmdToGetIps)
-				if err != nil {
-					return
-				}
-
-				n := strings.Split(nodeIps, "\n")
-				for _, nodeIP := range n {
-					if nodeIP != "" {
-						nodes = append(nodes, nodeIP)
-					}
-				}
-
-				ips = nodes
-			}
+			expectedValues := assert.SplitExpected(testMap.ExpectedValue)
 
-			for _, ip := range ips {
+			for _, ip := range targets[i] {
 				processCmds(resultChan, wg, ip, cmds, expectedValues)
 			}
 		}
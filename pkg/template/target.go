@@ -0,0 +1,82 @@
+package template
+
+import (
+	"strings"
+
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const (
+	targetAll          = "all"
+	targetServers      = "servers"
+	targetAgents       = "agents"
+	targetEtcd         = "etcd"
+	targetControlPlane = "control-plane"
+	targetWindows      = "windows"
+	targetHost         = "host"
+	targetLabelPrefix  = "label:"
+
+	etcdSelector         = "node-role.kubernetes.io/etcd=true"
+	controlPlaneSelector = "node-role.kubernetes.io/control-plane=true"
+	windowsSelector      = "kubernetes.io/os=windows"
+	agentSelector        = "!node-role.kubernetes.io/etcd,!node-role.kubernetes.io/control-plane," +
+		"kubernetes.io/os=linux"
+)
+
+// hostIP is the placeholder ip used when the commands target the host only.
+const hostIP = "host"
+
+// targetIPs returns the external IPs of the nodes the target resolves to.
+//
+// target = all, servers, agents, etcd, control-plane, windows, host or label:<selector>.
+func targetIPs(target string) ([]string, error) {
+	target = strings.TrimSpace(target)
+
+	switch {
+	case target == "" || target == targetAll:
+		return shared.FetchNodeExternalIP(), nil
+	case target == targetHost:
+		return []string{hostIP}, nil
+	case target == targetEtcd:
+		return shared.FetchNodeExternalIPBySelector(etcdSelector)
+	case target == targetControlPlane:
+		return shared.FetchNodeExternalIPBySelector(controlPlaneSelector)
+	case target == targetWindows:
+		return shared.FetchNodeExternalIPBySelector(windowsSelector)
+	case target == targetAgents:
+		return shared.FetchNodeExternalIPBySelector(agentSelector)
+	case target == targetServers:
+		return serverIPs()
+	case strings.HasPrefix(target, targetLabelPrefix):
+		selector := strings.TrimPrefix(target, targetLabelPrefix)
+		if selector == "" {
+			return nil, shared.ReturnLogError("label target should have a selector")
+		}
+
+		return shared.FetchNodeExternalIPBySelector(selector)
+	default:
+		return nil, shared.ReturnLogError("invalid target: %s", target)
+	}
+}
+
+// serverIPs returns the external IPs of the etcd and control-plane nodes without duplicates.
+func serverIPs() ([]string, error) {
+	var ips []string
+	seen := map[string]bool{}
+
+	for _, selector := range []string{etcdSelector, controlPlaneSelector} {
+		nodeIPs, err := shared.FetchNodeExternalIPBySelector(selector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, ip := range nodeIPs {
+			if !seen[ip] {
+				seen[ip] = true
+				ips = append(ips, ip)
+			}
+		}
+	}
+
+	return ips, nil
+}
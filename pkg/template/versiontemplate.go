@@ -4,17 +4,25 @@ import (
 	"strings"
 
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/report"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/gomega"
 )
 
 func VersionTemplate(test VersionTestTemplate) {
-	if customflag.ServiceFlag.TestConfig.WorkloadName != "" &&
-		strings.HasSuffix(customflag.ServiceFlag.TestConfig.WorkloadName, ".yaml") {
+	report.AddProperty("description", test.Description)
+	report.AddProperty("install_version_or_commit", test.InstallMode)
+
+	workloadName := customflag.ServiceFlag.TestConfig.WorkloadName
+	if test.TestConfig != nil && test.TestConfig.WorkloadName != "" {
+		workloadName = test.TestConfig.WorkloadName
+	}
+
+	if workloadName != "" && strings.HasSuffix(workloadName, ".yaml") {
 		_, err := shared.ManageWorkload(
 			"apply",
-			customflag.ServiceFlag.TestConfig.WorkloadName,
+			workloadName,
 		)
 		Expect(err).NotTo(HaveOccurred())
 	}
@@ -26,11 +34,8 @@ func VersionTemplate(test VersionTestTemplate) {
 		upgErr := upgradeVersion(test, test.InstallMode)
 		Expect(upgErr).NotTo(HaveOccurred(), "error upgrading version: %v", upgErr)
 
+		// executeTestCombination already runs the test cases after checking the upgraded version.
 		err = executeTestCombination(test)
 		Expect(err).NotTo(HaveOccurred(), "error checking version: %v", err)
-
-		if test.TestConfig != nil {
-			testCaseWrapper(test)
-		}
 	}
 }
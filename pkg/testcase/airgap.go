@@ -0,0 +1,148 @@
+package testcase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const airgapNodeDir = "/tmp/distros-airgap"
+
+// nodeInstallCmd returns the command that installs version on the node, staging the airgap artifacts
+// on the node first when the install mode is airgap.
+func nodeInstallCmd(product, version, nodeType, ip string) (string, error) {
+	if !customflag.ServiceFlag.InstallMode.Airgap {
+		return getInstallCmd(version, nodeType), nil
+	}
+
+	remoteDir, err := stageAirgap(product, version, ip)
+	if err != nil {
+		return "", err
+	}
+
+	return getAirgapInstallCmd(product, nodeType, remoteDir), nil
+}
+
+// stageAirgap copies the install script, binaries and image tarballs of the version for the node arch
+// to the node and moves the images and the k3s binary to where the product picks them up without downloading.
+func stageAirgap(product, version, ip string) (string, error) {
+	arch := releaseArch(shared.Arch)
+	files, err := airgapArtifacts(product, version, arch)
+	if err != nil {
+		return "", err
+	}
+
+	remoteDir := airgapNodeDir + "/" + version
+	fmt.Printf("Staging airgap artifacts of %s on: %s\n", version, ip)
+	if err = shared.CopyToNode(ip, remoteDir, files...); err != nil {
+		return "", err
+	}
+
+	imagesDir := fmt.Sprintf("/var/lib/rancher/%s/agent/images", product)
+	stageCmd := fmt.Sprintf("sudo mkdir -p %s && sudo cp %s/*images* %s/", imagesDir, remoteDir, imagesDir)
+	if product == "k3s" {
+		binary := remoteDir + "/" + k3sBinary(arch)
+		stageCmd += fmt.Sprintf(" && sudo install -m 755 %s /usr/local/bin/k3s", binary)
+	}
+
+	if _, err = shared.RunCommandOnNode(stageCmd, ip); err != nil {
+		return "", shared.ReturnLogError("failed to stage airgap artifacts on %s: %w", ip, err)
+	}
+
+	return remoteDir, nil
+}
+
+// airgapArtifacts returns the files of <airgap_dir>/<version> for the arch, which must hold the install
+// script, the images tarball and the k3s binary or the rke2 tarball with its checksums.
+//
+// Files of other arches are skipped, the arch ones are named <name>-<arch>.<ext> as released,
+// except the k3s binary, see k3sBinary.
+func airgapArtifacts(product, version, arch string) ([]string, error) {
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Upgrade.AirgapDir == "" {
+		return nil, shared.ReturnLogError("airgap install mode requires upgrade.airgap_dir to be set")
+	}
+
+	dir := filepath.Join(cfg.Upgrade.AirgapDir, version)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to read airgap artifacts %s: %w", dir, err)
+	}
+
+	var files []string
+	found := map[string]bool{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		archFile := strings.Contains(name, "-"+arch+".")
+		switch {
+		case name == "install.sh":
+			found["install.sh"] = true
+		case product == "k3s" && name == k3sBinary(arch):
+			found["binary"] = true
+		case !archFile:
+			continue
+		case strings.Contains(name, "images"):
+			found["images"] = true
+		case product == "rke2" && strings.HasPrefix(name, "rke2.linux-"):
+			found["binary"] = true
+		case product == "rke2" && strings.HasPrefix(name, "sha256sum-"):
+			found["checksums"] = true
+		}
+		files = append(files, filepath.Join(dir, name))
+	}
+
+	required := []string{"install.sh", "images", "binary"}
+	if product == "rke2" {
+		required = append(required, "checksums")
+	}
+	for _, r := range required {
+		if !found[r] {
+			return nil, shared.ReturnLogError("airgap artifacts %s missing the %s %s %s", dir, arch, product, r)
+		}
+	}
+
+	return files, nil
+}
+
+// releaseArch returns the release arch of the tfvars arch,
+// arm clusters run arm64 as the install scripts map it from uname.
+func releaseArch(arch string) string {
+	switch arch {
+	case "", "amd64":
+		return "amd64"
+	case "arm":
+		return "arm64"
+	}
+
+	return arch
+}
+
+// k3sBinary returns the name the k3s binary of the arch is released with.
+func k3sBinary(arch string) string {
+	if arch == "amd64" {
+		return "k3s"
+	}
+
+	return "k3s-" + arch
+}
+
+// getAirgapInstallCmd returns the install command that runs the staged install script without downloading.
+func getAirgapInstallCmd(product, nodeType, remoteDir string) string {
+	if product == "rke2" {
+		return fmt.Sprintf("sudo INSTALL_RKE2_ARTIFACT_PATH=%s INSTALL_RKE2_TYPE=%s sh %s/install.sh",
+			remoteDir, nodeType, remoteDir)
+	}
+
+	return fmt.Sprintf("sudo INSTALL_K3S_SKIP_DOWNLOAD=true sh %s/install.sh %s", remoteDir, nodeType)
+}
@@ -0,0 +1,182 @@
+package testcase
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// certInfo is the identity of a certificate file, used to tell whether it was rotated.
+type certInfo struct {
+	serial   string
+	notAfter time.Time
+}
+
+// TestCertRotation rotates the certificates of the -certServices services on each server, all when not set.
+func TestCertRotation(deleteWorkload bool) {
+	var services []string
+	for _, s := range customflag.CertServicesFlag {
+		if s = strings.TrimSpace(s); s != "" {
+			services = append(services, s)
+		}
+	}
+
+	TestCertRotationServices(services)
+}
+
+// TestCertRotationServices stops each server, runs certificate rotate for the services, or all when empty,
+// and starts it again, then asserts the certificates of the services have new serials, the others
+// kept theirs, the node kubeconfig works and the nodes and pods are healthy.
+func TestCertRotationServices(services []string) {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for certificate rotation")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	rotated, kept := rotationFiles(product, services)
+	service := serverService(product)
+	rotateCmd := fmt.Sprintf("sudo %s certificate rotate", product)
+	if len(services) > 0 {
+		rotateCmd += " --service " + strings.Join(services, ",")
+	}
+
+	for _, ip := range cluster.ServerIPs {
+		before, err := serverCerts(product, ip)
+		Expect(err).NotTo(HaveOccurred(), err)
+
+		fmt.Printf("\nRotating certificates on %s: %s\n", ip, rotateCmd)
+		_, err = shared.RunCommandOnNode("sudo systemctl stop "+service, ip)
+		Expect(err).NotTo(HaveOccurred(), "failed to stop %s on %s: %v", service, ip, err)
+
+		_, err = shared.RunCommandOnNode(rotateCmd, ip)
+		Expect(err).NotTo(HaveOccurred(), "failed to rotate certificates on %s: %v", ip, err)
+
+		_, err = shared.RunCommandOnNode("sudo systemctl start "+service, ip)
+		Expect(err).NotTo(HaveOccurred(), "failed to start %s on %s: %v", service, ip, err)
+
+		after, err := serverCerts(product, ip)
+		Expect(err).NotTo(HaveOccurred(), err)
+
+		for file, cert := range before {
+			newCert, ok := after[file]
+			if !ok {
+				continue
+			}
+
+			switch {
+			case matchesAny(file, rotated):
+				Expect(newCert.serial).NotTo(Equal(cert.serial), "certificate %s on %s was not rotated", file, ip)
+				fmt.Printf("Rotated %s, valid until %s\n", file, newCert.notAfter.Format(time.RFC3339))
+			case matchesAny(file, kept):
+				Expect(newCert.serial).To(Equal(cert.serial), "certificate %s on %s should not be rotated", file, ip)
+			}
+		}
+	}
+
+	for _, ip := range cluster.AgentIPs {
+		err = shared.RestartCluster(product, ip)
+		Expect(err).NotTo(HaveOccurred(), err)
+	}
+
+	for _, ip := range cluster.ServerIPs {
+		err = assert.ValidateOnNode(ip, nodeKubectl(product)+" get nodes", "Ready")
+		Expect(err).NotTo(HaveOccurred(), "kubeconfig on %s does not work after rotation: %v", ip, err)
+	}
+
+	TestNodeStatus(assert.NodeAssertReadyStatus(), nil)
+	TestPodStatus(nil, assert.PodAssertReady(), assert.PodAssertStatus())
+}
+
+// rotationFiles returns the certificate files expected to rotate for the services
+// and the ones of the other services that should keep their serials.
+func rotationFiles(product string, services []string) (rotated, kept []string) {
+	files := map[string][]string{
+		"admin":                 {"client-admin.crt"},
+		"api-server":            {"client-kube-apiserver.crt", "serving-kube-apiserver.crt"},
+		"controller-manager":    {"client-controller.crt"},
+		"scheduler":             {"client-scheduler.crt"},
+		"etcd":                  {"etcd/client.crt", "etcd/server-client.crt", "etcd/peer-server-client.crt"},
+		"auth-proxy":            {"client-auth-proxy.crt"},
+		"kube-proxy":            {"client-kube-proxy.crt"},
+		product + "-controller": {"client-" + product + "-controller.crt"},
+		"cloud-controller":      {"client-" + product + "-cloud-controller.crt"},
+	}
+
+	selected := map[string]bool{}
+	for _, s := range services {
+		selected[s] = true
+	}
+
+	for name, certs := range files {
+		if len(services) == 0 || selected[name] {
+			rotated = append(rotated, certs...)
+		} else {
+			kept = append(kept, certs...)
+		}
+	}
+
+	return rotated, kept
+}
+
+func matchesAny(file string, names []string) bool {
+	for _, name := range names {
+		if file == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// serverCerts returns the first certificate of each file under the server tls dir, by path relative to it.
+func serverCerts(product, ip string) (map[string]certInfo, error) {
+	tlsDir := fmt.Sprintf("/var/lib/rancher/%s/server/tls", product)
+	cmd := fmt.Sprintf("sudo sh -c 'cd %s && for f in *.crt etcd/*.crt; do "+
+		"[ -f \"$f\" ] && echo \"==> $f\" && cat \"$f\"; done; true'", tlsDir)
+
+	res, err := shared.RunCommandOnNode(cmd, ip)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to read certificates on %s: %w", ip, err)
+	}
+
+	certs := map[string]certInfo{}
+	for _, section := range strings.Split(res, "==> ")[1:] {
+		name, content, _ := strings.Cut(section, "\n")
+		block, _ := pem.Decode([]byte(content))
+		if block == nil {
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, shared.ReturnLogError("failed to parse %s on %s: %w", name, ip, err)
+		}
+		certs[strings.TrimSpace(name)] = certInfo{serial: cert.SerialNumber.String(), notAfter: cert.NotAfter}
+	}
+
+	if len(certs) == 0 {
+		return nil, shared.ReturnLogError("no certificates found on %s:%s", ip, tlsDir)
+	}
+
+	return certs, nil
+}
+
+// nodeKubectl returns the kubectl command using the kubeconfig the product writes on the server.
+func nodeKubectl(product string) string {
+	if product == "rke2" {
+		return "sudo /var/lib/rancher/rke2/bin/kubectl --kubeconfig /etc/rancher/rke2/rke2.yaml"
+	}
+
+	return "sudo k3s kubectl"
+}
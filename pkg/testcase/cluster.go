@@ -18,18 +18,20 @@ func TestBuildCluster(g GinkgoTInterface) {
 	Expect(shared.KubeConfigFile).ShouldNot(BeEmpty())
 	Expect(cluster.ServerIPs).ShouldNot(BeEmpty())
 
-	if strings.Contains(cluster.Config.DataStore, "etcd") {
-		fmt.Println("Backend:", cluster.Config.DataStore)
-	} else {
-		fmt.Println("Backend:", cluster.Config.ExternalDb)
-	}
+	fmt.Println("Backend:", cluster.Config.Backend())
 
 	if cluster.Config.ExternalDb != "" && cluster.Config.DataStore == "" {
-		for i := 0; i > len(cluster.ServerIPs); i++ {
+		for i, ip := range cluster.ServerIPs {
+			// the container datastores run on the first server, the others reach it on its private ip.
+			expected := cluster.Config.RenderedTemplate
+			if i > 0 && strings.HasSuffix(cluster.Config.ExternalDb, "-container") {
+				expected, _, _ = strings.Cut(expected, "127.0.0.1")
+			}
+
 			cmd := "grep \"datastore-endpoint\" /etc/systemd/system/k3s.service"
-			res, err := shared.RunCommandOnNode(cmd, cluster.ServerIPs[0])
+			res, err := shared.RunCommandOnNode(cmd, ip)
 			Expect(err).NotTo(HaveOccurred())
-			Expect(res).Should(ContainSubstring(cluster.Config.RenderedTemplate))
+			Expect(res).Should(ContainSubstring(expected))
 		}
 	}
 
@@ -69,7 +71,7 @@ func TestSonobuoyMixedOS(deleteWorkload bool) {
 	cmd = fmt.Sprintf("sonobuoy results %s", testResultTar)
 	res, err = shared.RunCommandHost(cmd)
 	Expect(err).NotTo(HaveOccurred(), "failed cmd: "+cmd)
-	Expect(res).Should(ContainSubstring("Plugin: mixed-workload-e2e\nStatus: passed\n"))
+	Expect(res).Should(ContainSubstring("Plugin: mixed-workload-e2e\nStatus: passed"))
 
 	if deleteWorkload {
 		cmd = fmt.Sprintf("sonobuoy delete --all --wait --kubeconfig=%s", shared.KubeConfigFile)
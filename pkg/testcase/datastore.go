@@ -0,0 +1,64 @@
+package testcase
+
+import (
+	"fmt"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// TestDatastore asserts the servers run on the datastore the cluster was provisioned with,
+// from the embedded etcd metrics, the sqlite database file or the kine sql metrics and the
+// connection to the external db.
+func TestDatastore() {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for the datastore")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	backend := cluster.Config.Backend()
+	fmt.Printf("\nAsserting %s servers run on datastore: %s\n", product, backend)
+
+	if backend == "etcd" {
+		for _, ip := range cluster.ServerIPs {
+			err = assert.ValidateOnNode(ip, "curl -s http://127.0.0.1:2381/metrics", "etcd_server_has_leader 1")
+			Expect(err).NotTo(HaveOccurred(), "etcd has no leader on %s: %v", ip, err)
+		}
+
+		return
+	}
+
+	// the k3s log names the kine driver, mariadb and aurora-mysql run on the mysql one.
+	driver := "mysql"
+	switch backend {
+	case "sqlite":
+		driver = "sqlite3"
+	case "postgres", "postgres-container":
+		driver = "postgres"
+	}
+
+	for _, ip := range cluster.ServerIPs {
+		if backend == "sqlite" {
+			err = assert.ValidateOnNode(ip,
+				"sudo ls /var/lib/rancher/k3s/server/db/state.db", "state.db")
+			Expect(err).NotTo(HaveOccurred(), "no sqlite database on %s: %v", ip, err)
+		} else {
+			err = assert.ValidateOnNode(ip,
+				"grep datastore-endpoint /etc/systemd/system/k3s.service", driver+"://")
+			Expect(err).NotTo(HaveOccurred(), "server %s not configured with %s: %v", ip, backend, err)
+		}
+
+		journal := "sudo journalctl -u k3s --no-pager | grep 'database connection pooling' | tail -1"
+		err = assert.ValidateOnNode(ip, journal, "Configuring "+driver+" database connection pooling")
+		Expect(err).NotTo(HaveOccurred(), "server %s not connected to %s: %v", ip, backend, err)
+	}
+
+	getMetrics := "kubectl get --raw /metrics --kubeconfig=" + shared.KubeConfigFile
+	err = assert.ValidateOnHost(getMetrics, "kine_sql_total")
+	Expect(err).NotTo(HaveOccurred(), "no kine metrics on %s: %v", backend, err)
+}
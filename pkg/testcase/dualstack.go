@@ -0,0 +1,211 @@
+package testcase
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	ipv4Family         = "IPv4"
+	ipv6Family         = "IPv6"
+	dualStackNamespace = "test-dualstack"
+	dualStackHost      = "dualstack.test"
+)
+
+// TestIPFamilies asserts every node and pod has an ip of each family the cluster is configured with,
+// IPv4 and IPv6 on dual-stack clusters or IPv6 only on single-stack IPv6 clusters.
+func TestIPFamilies() {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for the ip families")
+
+	families, err := clusterFamilies(cluster.ServerIPs[0])
+	Expect(err).NotTo(HaveOccurred(), err)
+	fmt.Printf("\nCluster ip families: %v\n", families)
+
+	nodes, err := kube.GetNodes()
+	Expect(err).NotTo(HaveOccurred(), err)
+	for _, node := range nodes {
+		Expect(ipFamilies(node.InternalIPs)).To(ConsistOf(families),
+			"node %s internal ips %v do not match the cluster ip families", node.Name, node.InternalIPs)
+	}
+
+	pods, err := kube.GetPods("", "")
+	Expect(err).NotTo(HaveOccurred(), err)
+	for _, pod := range pods {
+		if len(pod.PodIPs) == 0 {
+			continue
+		}
+		Expect(ipFamilies(pod.PodIPs)).To(ConsistOf(families),
+			"pod %s/%s ips %v do not match the cluster ip families", pod.NameSpace, pod.Name, pod.PodIPs)
+	}
+}
+
+// TestDualStackServices deploys services with each ipFamilyPolicy the cluster supports and validates
+// ClusterIP, NodePort and ingress reachability over each ip family from the first server.
+func TestDualStackServices(deleteWorkload bool) {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for dual-stack services")
+
+	from := cluster.ServerIPs[0]
+	families, err := clusterFamilies(from)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	workloads := []string{"dualstack.yaml"}
+	expected := map[string][]string{"dualstack-prefer": families, "dualstack-nodeport": families}
+	for _, family := range families {
+		name := "dualstack-" + strings.ToLower(family)
+		workloads = append(workloads, name+".yaml")
+		expected[name] = []string{family}
+	}
+	if len(families) == 2 {
+		workloads = append(workloads, "dualstack-require.yaml")
+		expected["dualstack-require"] = families
+	}
+
+	_, err = shared.ManageWorkload("apply", workloads...)
+	Expect(err).NotTo(HaveOccurred(), "dual-stack manifests not deployed")
+
+	getPods := "kubectl get pods -n " + dualStackNamespace + " -l k8s-app=nginx-app-dualstack --kubeconfig="
+	err = assert.CheckComponentCmdHost(getPods+shared.KubeConfigFile, "test-dualstack", statusRunning)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	cidrs, err := serverConfigValue(from, "cluster-cidr")
+	Expect(err).NotTo(HaveOccurred(), err)
+	assert.ValidatePodIPByLabel([]string{"k8s-app=nginx-app-dualstack"}, []string{cidrs})
+
+	for name, serviceFamilies := range expected {
+		service, err := kube.GetService(dualStackNamespace, name)
+		Expect(err).NotTo(HaveOccurred(), err)
+		Expect(service.IPFamilies).To(ConsistOf(serviceFamilies),
+			"service %s with policy %s has ip families %v", name, service.IPFamilyPolicy, service.IPFamilies)
+		Expect(ipFamilies(service.ClusterIPs)).To(ConsistOf(serviceFamilies),
+			"service %s cluster ips %v", name, service.ClusterIPs)
+
+		for _, ip := range service.ClusterIPs {
+			err = assert.ValidateOnNode(from, curlCmd(ip, "8080", ""), "test-dualstack")
+			Expect(err).NotTo(HaveOccurred(), "service %s not reachable on %s: %v", name, ip, err)
+		}
+	}
+
+	nodePort, err := kube.GetService(dualStackNamespace, "dualstack-nodeport")
+	Expect(err).NotTo(HaveOccurred(), err)
+	Expect(nodePort.Ports).NotTo(BeEmpty())
+	port := strconv.Itoa(int(nodePort.Ports[0].NodePort))
+
+	nodes, err := kube.GetNodes()
+	Expect(err).NotTo(HaveOccurred(), err)
+	for _, node := range nodes {
+		for _, ip := range node.InternalIPs {
+			err = assert.ValidateOnNode(from, curlCmd(ip, port, ""), "test-dualstack")
+			Expect(err).NotTo(HaveOccurred(), "node port not reachable on %s %s: %v", node.Name, ip, err)
+
+			err = assert.ValidateOnNode(from, curlCmd(ip, "80", dualStackHost), "test-dualstack")
+			Expect(err).NotTo(HaveOccurred(), "ingress not reachable on %s %s: %v", node.Name, ip, err)
+		}
+	}
+
+	if deleteWorkload {
+		_, err = shared.ManageWorkload("delete", workloads...)
+		Expect(err).NotTo(HaveOccurred(), "dual-stack manifests not deleted")
+	}
+}
+
+// clusterFamilies returns the ip families of the cluster-cidr and service-cidr set on the server config,
+// failing when they are not set, differ, or the cluster is IPv4 only.
+func clusterFamilies(ip string) ([]string, error) {
+	var families []string
+	for _, key := range []string{"cluster-cidr", "service-cidr"} {
+		cidrs, err := serverConfigValue(ip, key)
+		if err != nil {
+			return nil, err
+		}
+		if cidrs == "" {
+			return nil, shared.ReturnLogError("%s not set on %s, the cluster is not dual-stack or IPv6", key, ip)
+		}
+
+		var ips []string
+		for _, cidr := range strings.Split(cidrs, ",") {
+			ips = append(ips, strings.Split(strings.TrimSpace(cidr), "/")[0])
+		}
+
+		keyFamilies := ipFamilies(ips)
+		if families != nil && strings.Join(families, ",") != strings.Join(keyFamilies, ",") {
+			return nil, shared.ReturnLogError("cluster-cidr families %v do not match service-cidr families %v",
+				families, keyFamilies)
+		}
+		families = keyFamilies
+	}
+
+	if len(families) == 1 && families[0] == ipv4Family {
+		return nil, shared.ReturnLogError("cluster on %s is configured IPv4 only, expected dual-stack or IPv6", ip)
+	}
+
+	return families, nil
+}
+
+// serverConfigValue returns the value of the key on the server config, empty when not set.
+func serverConfigValue(ip, key string) (string, error) {
+	product, err := shared.GetProduct()
+	if err != nil {
+		return "", err
+	}
+
+	cmd := fmt.Sprintf("sudo grep -h '^%s:' /etc/rancher/%s/config.yaml || true", key, product)
+	res, err := shared.RunCommandOnNode(cmd, ip)
+	if err != nil {
+		return "", shared.ReturnLogError("failed to read %s on %s: %w", key, ip, err)
+	}
+
+	_, value, found := strings.Cut(res, ":")
+	if !found {
+		return "", nil
+	}
+
+	return strings.Trim(strings.TrimSpace(value), `"'`), nil
+}
+
+// ipFamilies returns the distinct families of the ips, IPv4 first.
+func ipFamilies(ips []string) []string {
+	var v4, v6 bool
+	for _, ip := range ips {
+		parsed := net.ParseIP(ip)
+		switch {
+		case parsed == nil:
+		case parsed.To4() != nil:
+			v4 = true
+		default:
+			v6 = true
+		}
+	}
+
+	var families []string
+	if v4 {
+		families = append(families, ipv4Family)
+	}
+	if v6 {
+		families = append(families, ipv6Family)
+	}
+
+	return families
+}
+
+// curlCmd returns the curl command to the ip and port, with the host header when set.
+// It always exits 0 so the assertion retries until the command timeout instead of failing on the first try.
+func curlCmd(ip, port, host string) string {
+	cmd := "curl -sgL -m 5 --insecure"
+	if host != "" {
+		cmd += " --header host:" + host
+	}
+
+	return cmd + " http://" + net.JoinHostPort(ip, port) + "/name.html || true"
+}
@@ -0,0 +1,163 @@
+package testcase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/pkg/report"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	etcdMarkerNamespace = "test-etcd-marker"
+	etcdSnapshotName    = "distros-test-snapshot"
+)
+
+// TestEtcdSnapshotRestore takes an on-demand etcd snapshot, writes a marker workload,
+// restores the snapshot with cluster-reset on the first etcd server, rejoins the other servers
+// and asserts the cluster is back to the state before the marker.
+func TestEtcdSnapshotRestore(deleteWorkload bool) {
+	cluster := factory.AddCluster(GinkgoT())
+	if cluster.Config.Backend() != "etcd" {
+		// test cases share the version bump spec, skipping it would skip the test cases after this one.
+		shared.LogLevel("warn", "skipping etcd snapshot restore, needs embedded etcd, datastore: %s",
+			cluster.Config.Backend())
+		report.AddProperty("etcd_snapshot_restore", "skipped, datastore "+cluster.Config.Backend())
+		return
+	}
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for etcd snapshot restore")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	servers, err := etcdFirst(cluster.ServerIPs)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	snapshotPath, err := saveEtcdSnapshot(product, servers[0])
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	_, err = shared.ManageWorkload("apply", "etcd-marker.yaml")
+	Expect(err).NotTo(HaveOccurred(), "etcd marker manifest not deployed")
+
+	getNamespaces := "kubectl get namespaces --kubeconfig=" + shared.KubeConfigFile
+	err = assert.ValidateOnHost(getNamespaces, etcdMarkerNamespace)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	err = restoreEtcdSnapshot(product, snapshotPath, servers)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	TestNodeStatus(assert.NodeAssertReadyStatus(), nil)
+	TestPodStatus(nil, assert.PodAssertReady(), assert.PodAssertStatus())
+
+	err = assert.ValidateOnHost(getNamespaces, "notContains("+etcdMarkerNamespace+")")
+	Expect(err).NotTo(HaveOccurred(), "marker workload should not exist after restore: %v", err)
+
+	if deleteWorkload {
+		_, err = shared.RunCommandHost("kubectl delete namespace " + etcdMarkerNamespace +
+			" --ignore-not-found --kubeconfig=" + shared.KubeConfigFile)
+		Expect(err).NotTo(HaveOccurred(), "etcd marker namespace not deleted")
+	}
+}
+
+// saveEtcdSnapshot takes an on-demand snapshot on the server and returns its path on the node.
+func saveEtcdSnapshot(product, ip string) (string, error) {
+	fmt.Printf("\nSaving etcd snapshot on: %s\n", ip)
+
+	saveCmd := fmt.Sprintf("sudo %s etcd-snapshot save --name %s", product, etcdSnapshotName)
+	if _, err := shared.RunCommandOnNode(saveCmd, ip); err != nil {
+		return "", shared.ReturnLogError("failed to save etcd snapshot: %w", err)
+	}
+
+	snapshotDir := fmt.Sprintf("/var/lib/rancher/%s/server/db/snapshots", product)
+	findCmd := fmt.Sprintf("sudo ls -t %s | grep %s | head -1", snapshotDir, etcdSnapshotName)
+	res, err := shared.RunCommandOnNode(findCmd, ip)
+	snapshot, _, _ := strings.Cut(strings.TrimSpace(res), "\n")
+	if err != nil || snapshot == "" {
+		return "", shared.ReturnLogError("failed to find etcd snapshot on %s: %v", snapshotDir, err)
+	}
+
+	return snapshotDir + "/" + snapshot, nil
+}
+
+// restoreEtcdSnapshot stops all servers, resets the first one from the snapshot
+// and rejoins the remaining servers with a clean etcd data dir.
+//
+// the first server must be the etcd server the snapshot was saved on, see etcdFirst.
+func restoreEtcdSnapshot(product, snapshotPath string, serverIPs []string) error {
+	service := serverService(product)
+
+	for _, ip := range serverIPs {
+		if _, err := shared.RunCommandOnNode("sudo systemctl stop "+service, ip); err != nil {
+			return shared.ReturnLogError("failed to stop %s on %s: %w", service, ip, err)
+		}
+	}
+
+	firstServer := serverIPs[0]
+	fmt.Printf("\nRestoring etcd snapshot %s on: %s\n", snapshotPath, firstServer)
+	resetCmd := fmt.Sprintf("sudo %s server --cluster-reset --cluster-reset-restore-path=%s",
+		product, snapshotPath)
+	if _, err := shared.RunCommandOnNode(resetCmd, firstServer); err != nil {
+		return shared.ReturnLogError("failed to restore etcd snapshot on %s: %w", firstServer, err)
+	}
+
+	if _, err := shared.RunCommandOnNode("sudo systemctl start "+service, firstServer); err != nil {
+		return shared.ReturnLogError("failed to start %s on %s: %w", service, firstServer, err)
+	}
+
+	for _, ip := range serverIPs[1:] {
+		fmt.Printf("\nRejoining server: %s\n", ip)
+		rejoinCmd := fmt.Sprintf("sudo rm -rf /var/lib/rancher/%s/server/db && sudo systemctl start %s",
+			product, service)
+		if _, err := shared.RunCommandOnNode(rejoinCmd, ip); err != nil {
+			return shared.ReturnLogError("failed to rejoin server %s: %w", ip, err)
+		}
+	}
+
+	return nil
+}
+
+// etcdFirst returns the server ips with the servers labelled etcd first,
+// since control-plane only servers of split-role clusters have no etcd to snapshot or reset.
+func etcdFirst(serverIPs []string) ([]string, error) {
+	nodes, err := kube.GetNodesBySelector("node-role.kubernetes.io/etcd")
+	if err != nil {
+		return nil, err
+	}
+
+	etcdIPs := map[string]bool{}
+	for _, node := range nodes {
+		etcdIPs[node.ExternalIP] = true
+		for _, ip := range node.InternalIPs {
+			etcdIPs[ip] = true
+		}
+	}
+
+	var etcd, others []string
+	for _, ip := range serverIPs {
+		if etcdIPs[ip] {
+			etcd = append(etcd, ip)
+		} else {
+			others = append(others, ip)
+		}
+	}
+	if len(etcd) == 0 {
+		return nil, shared.ReturnLogError("no etcd server found in %v", serverIPs)
+	}
+
+	return append(etcd, others...), nil
+}
+
+// serverService returns the systemd service name of the product servers.
+func serverService(product string) string {
+	if product == "rke2" {
+		return "rke2-server"
+	}
+
+	return "k3s"
+}
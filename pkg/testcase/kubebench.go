@@ -0,0 +1,235 @@
+package testcase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"gopkg.in/yaml.v3"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const kubeBenchNamespace = "kube-bench"
+
+// cisSysctls are the kernel parameters the kubelet expects with protect-kernel-defaults.
+var cisSysctls = map[string]string{
+	"vm.panic_on_oom":      "0",
+	"vm.overcommit_memory": "1",
+	"kernel.panic":         "10",
+	"kernel.panic_on_oops": "1",
+}
+
+// benchAllowlist is the kube-bench benchmark and the FAIL checks expected on a minor version.
+type benchAllowlist struct {
+	Benchmark string         `yaml:"benchmark"`
+	Allowed   []allowedCheck `yaml:"allowed"`
+}
+
+type allowedCheck struct {
+	Check  string `yaml:"check"`
+	Reason string `yaml:"reason"`
+}
+
+// benchOutput is the part of the kube-bench json output the results are read from.
+type benchOutput struct {
+	Controls []struct {
+		NodeType string `json:"node_type"`
+		Tests    []struct {
+			Results []benchResult `json:"results"`
+		} `json:"tests"`
+	} `json:"Controls"`
+}
+
+type benchResult struct {
+	TestNumber string `json:"test_number"`
+	TestDesc   string `json:"test_desc"`
+	Status     string `json:"status"`
+}
+
+// TestCISProfile asserts the nodes were provisioned for the CIS profile, with the kernel parameters
+// the kubelet protects, the etcd user on the rke2 servers and the pod security admission config.
+func TestCISProfile() {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for the cis profile")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	ips := append(append([]string{}, cluster.ServerIPs...), cluster.AgentIPs...)
+	for _, ip := range ips {
+		for key, value := range cisSysctls {
+			err = assert.ValidateOnNode(ip, "sudo sysctl -n "+key, "exact("+value+")")
+			Expect(err).NotTo(HaveOccurred(), "sysctl %s on %s: %v", key, ip, err)
+		}
+	}
+
+	for _, ip := range cluster.ServerIPs {
+		if product == "rke2" {
+			err = assert.ValidateOnNode(ip,
+				"sudo cat /etc/rancher/rke2/config.yaml", "profile: cis",
+				"id etcd", "(etcd)",
+				"sudo cat /etc/rancher/rke2/rke2-pss.yaml", "restricted",
+			)
+		} else {
+			err = assert.ValidateOnNode(ip,
+				"sudo cat /etc/rancher/k3s/config.yaml", "protect-kernel-defaults: true",
+				"sudo cat /var/lib/rancher/k3s/server/cluster-level-pss.yaml", "restricted",
+			)
+		}
+		Expect(err).NotTo(HaveOccurred(), "server %s not provisioned with the cis profile: %v", ip, err)
+	}
+}
+
+// TestKubeBench runs kube-bench as a job on a control-plane, an etcd and a worker node, with the benchmark
+// of the cluster minor version in the allowlist file, and fails on any FAIL result the allowlist
+// does not expect.
+func TestKubeBench(allowlistPath string, deleteWorkload bool) {
+	current, err := clusterVersion()
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	allowlist, err := loadBenchAllowlist(allowlistPath, current)
+	Expect(err).NotTo(HaveOccurred(), err)
+	fmt.Printf("\nRunning kube-bench %s on %s\n", allowlist.Benchmark, current)
+
+	jobs := []string{"kube-bench-controlplane"}
+	workloads := []string{"kube-bench.yaml"}
+
+	// the etcd and node jobs only schedule on etcd and agent nodes, skipped when the cluster has none.
+	var hasEtcd, hasAgent bool
+	nodes, err := kube.GetNodes()
+	Expect(err).NotTo(HaveOccurred(), err)
+	for _, node := range nodes {
+		if node.Labels["node-role.kubernetes.io/etcd"] == "true" {
+			hasEtcd = true
+		}
+		if _, ok := node.Labels["node-role.kubernetes.io/control-plane"]; !ok {
+			hasAgent = true
+		}
+	}
+	if hasEtcd {
+		jobs = append(jobs, "kube-bench-etcd")
+		workloads = append(workloads, "kube-bench-etcd.yaml")
+	}
+	if hasAgent {
+		jobs = append(jobs, "kube-bench-node")
+		workloads = append(workloads, "kube-bench-node.yaml")
+	} else {
+		shared.LogLevel("warn", "skipping kube-bench node checks, no agent nodes found")
+	}
+
+	_, err = shared.ManageWorkload("apply", workloads...)
+	Expect(err).NotTo(HaveOccurred(), "kube-bench manifests not deployed")
+
+	// the jobs wait on the configmap to start, it holds the benchmark of the cluster version.
+	kubeconfigFlag := " --kubeconfig=" + shared.KubeConfigFile
+	createConfig := "kubectl create configmap kube-bench -n " + kubeBenchNamespace +
+		" --from-literal=benchmark=" + allowlist.Benchmark + " --dry-run=client -o yaml" + kubeconfigFlag +
+		" | kubectl apply -f -" + kubeconfigFlag
+	_, err = shared.RunCommandHost(createConfig)
+	Expect(err).NotTo(HaveOccurred(), "kube-bench configmap not created: %v", err)
+
+	var unexpected []string
+	for _, job := range jobs {
+		getJob := "kubectl get job " + job + " -n " + kubeBenchNamespace + " -o jsonpath='{.status.succeeded}'"
+		err = assert.ValidateOnHost(getJob+kubeconfigFlag, "exact(1)")
+		Expect(err).NotTo(HaveOccurred(), "kube-bench job %s did not complete: %v", job, err)
+
+		logs, err := shared.RunCommandHost("kubectl logs job/" + job + " -n " + kubeBenchNamespace + kubeconfigFlag)
+		Expect(err).NotTo(HaveOccurred(), "failed to get kube-bench job %s logs: %v", job, err)
+
+		failed, err := benchFailures(job, logs, allowlist)
+		Expect(err).NotTo(HaveOccurred(), err)
+		unexpected = append(unexpected, failed...)
+	}
+
+	Expect(unexpected).To(BeEmpty(), "unexpected kube-bench FAIL results:\n%s", strings.Join(unexpected, "\n"))
+
+	if deleteWorkload {
+		_, err = shared.ManageWorkload("delete", workloads...)
+		Expect(err).NotTo(HaveOccurred(), "kube-bench manifests not deleted")
+	}
+}
+
+// loadBenchAllowlist reads the allowlist of the minor version of current from the yaml file.
+func loadBenchAllowlist(path, current string) (*benchAllowlist, error) {
+	v, err := version.NewVersion(current)
+	if err != nil {
+		return nil, shared.ReturnLogError("invalid cluster version %s: %w", current, err)
+	}
+	minor := fmt.Sprintf("v%d.%d", v.Segments()[0], v.Segments()[1])
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, shared.ReturnLogError("failed to read kube-bench allowlist: %w", err)
+	}
+
+	allowlists := map[string]benchAllowlist{}
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err = decoder.Decode(&allowlists); err != nil {
+		return nil, shared.ReturnLogError("failed to parse kube-bench allowlist %s: %w", path, err)
+	}
+
+	allowlist, ok := allowlists[minor]
+	if !ok || allowlist.Benchmark == "" {
+		return nil, shared.ReturnLogError("kube-bench allowlist %s has no benchmark for %s", path, minor)
+	}
+
+	return &allowlist, nil
+}
+
+// benchFailures prints the results of the job and returns its FAIL checks the allowlist does not expect.
+func benchFailures(job, logs string, allowlist *benchAllowlist) ([]string, error) {
+	// kubectl logs mixes the kube-bench warnings on stderr with the json on stdout.
+	start, end := strings.Index(logs, `{"Controls"`), strings.LastIndex(logs, "}")
+	if start < 0 || end < start {
+		return nil, shared.ReturnLogError("no kube-bench json output on job %s:\n%s", job, logs)
+	}
+
+	var output benchOutput
+	if err := json.Unmarshal([]byte(logs[start:end+1]), &output); err != nil {
+		return nil, shared.ReturnLogError("failed to parse kube-bench output on job %s: %w", job, err)
+	}
+
+	allowed := map[string]string{}
+	for _, a := range allowlist.Allowed {
+		allowed[a.Check] = a.Reason
+	}
+
+	fmt.Printf("\nkube-bench %s results:\n", job)
+	var unexpected []string
+	counts := map[string]int{}
+	for _, control := range output.Controls {
+		for _, test := range control.Tests {
+			for _, result := range test.Results {
+				counts[result.Status]++
+				if result.Status != "FAIL" {
+					continue
+				}
+
+				if reason, ok := allowed[result.TestNumber]; ok {
+					fmt.Printf("%-10s%-8s%s (%s)\n", "ALLOWED", result.TestNumber, result.TestDesc, reason)
+					continue
+				}
+				fmt.Printf("%-10s%-8s%s\n", "FAIL", result.TestNumber, result.TestDesc)
+				unexpected = append(unexpected, fmt.Sprintf("%s %s %s", control.NodeType, result.TestNumber,
+					result.TestDesc))
+			}
+		}
+	}
+
+	fmt.Printf("%d PASS, %d FAIL, %d WARN, %d INFO\n",
+		counts["PASS"], counts["FAIL"], counts["WARN"], counts["INFO"])
+
+	return unexpected, nil
+}
@@ -39,7 +39,8 @@ func TestLocalPathProvisionerStorage(deleteWorkload bool) {
 
 	ips := shared.FetchNodeExternalIP()
 	for _, ip := range ips {
-		shared.RestartCluster("k3s", ip)
+		err = shared.RestartCluster("k3s", ip)
+		Expect(err).NotTo(HaveOccurred(), err)
 	}
 
 	_, err = shared.ReadDataPod(lps)
@@ -17,7 +17,7 @@ func TestInternodeConnectivityMixedOS(deleteWorkload bool) {
 		"pod_client.yaml", "windows_app_deployment.yaml")
 	Expect(err).NotTo(HaveOccurred())
 
-	assert.ValidatePodIPByLabel([]string{"app=client", "app=windows-app"}, []string{"10.42", "10.42"})
+	assert.ValidatePodIPByLabel([]string{"app=client", "app=windows-app"}, []string{"10.42.0.0/16", "10.42.0.0/16"})
 
 	err = testCrossNodeService(
 		[]string{"client-curl", "windows-app-svc"},
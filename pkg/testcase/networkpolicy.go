@@ -0,0 +1,143 @@
+package testcase
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/gomega"
+)
+
+const (
+	netpolServer  = "test-netpol-server"
+	netpolAllowed = "test-netpol-allowed"
+	netpolDenied  = "test-netpol-denied"
+)
+
+// netpolCase is a connection from the client pods of a namespace to a service
+// and whether it should go through.
+type netpolCase struct {
+	from      string
+	to        string
+	reachable bool
+}
+
+// netpolStage is a policy manifest applied on top of the previous ones
+// and the connectivity expected after it.
+type netpolStage struct {
+	name     string
+	workload string
+	cases    []netpolCase
+}
+
+// TestNetworkPolicy deploys a server and client pods on every node across namespaces, then applies
+// a default-deny, a namespace scoped allow and an egress policy in turn, asserting the allowed
+// and blocked connections after each.
+func TestNetworkPolicy(deleteWorkload bool) {
+	_, err := shared.ManageWorkload("apply", "networkpolicy.yaml")
+	Expect(err).NotTo(HaveOccurred(), "network policy manifest not deployed")
+
+	kubeconfigFlag := " --kubeconfig=" + shared.KubeConfigFile
+	getServer := "kubectl get pods -n " + netpolServer + " -l k8s-app=netpol-server" + kubeconfigFlag
+	err = assert.CheckComponentCmdHost(getServer, "netpol-server", statusRunning)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	for _, ns := range []string{netpolAllowed, netpolDenied} {
+		getClient := "kubectl get pods -n " + ns + " -l k8s-app=netpol-client" + kubeconfigFlag
+		err = assert.CheckComponentCmdHost(getClient, "netpol-client", statusRunning)
+		Expect(err).NotTo(HaveOccurred(), err)
+	}
+
+	stages := []netpolStage{
+		{"no policy", "", []netpolCase{
+			{netpolAllowed, netpolServer, true},
+			{netpolDenied, netpolServer, true},
+			{netpolAllowed, netpolDenied, true},
+			{netpolDenied, netpolAllowed, true},
+		}},
+		{"default deny", "networkpolicy-deny.yaml", []netpolCase{
+			{netpolAllowed, netpolServer, false},
+			{netpolDenied, netpolServer, false},
+			{netpolAllowed, netpolDenied, true},
+		}},
+		{"allow namespace", "networkpolicy-allow.yaml", []netpolCase{
+			{netpolAllowed, netpolServer, true},
+			{netpolDenied, netpolServer, false},
+		}},
+		{"egress", "networkpolicy-egress.yaml", []netpolCase{
+			{netpolAllowed, netpolServer, true},
+			{netpolDenied, netpolServer, false},
+			{netpolAllowed, netpolDenied, false},
+			{netpolDenied, netpolAllowed, true},
+		}},
+	}
+
+	workloads := []string{"networkpolicy.yaml"}
+	for _, stage := range stages {
+		if stage.workload != "" {
+			_, err = shared.ManageWorkload("apply", stage.workload)
+			Expect(err).NotTo(HaveOccurred(), "network policy %s not applied", stage.workload)
+			workloads = append(workloads, stage.workload)
+		}
+
+		table := fmt.Sprintf("\nNetwork policy stage: %s\n%-22s%-22s%-10s%s\n",
+			stage.name, "FROM", "TO", "EXPECTED", "NODES")
+		for _, c := range stage.cases {
+			nodes, err := assertNetpolCase(c)
+			Expect(err).NotTo(HaveOccurred(), "stage %s: %v", stage.name, err)
+			table += fmt.Sprintf("%-22s%-22s%-10s%s\n",
+				c.from, c.to, netpolResult(c.reachable), strings.Join(nodes, ","))
+		}
+		fmt.Print(table)
+	}
+
+	if deleteWorkload {
+		for i := len(workloads) - 1; i >= 0; i-- {
+			_, err = shared.ManageWorkload("delete", workloads[i])
+			Expect(err).NotTo(HaveOccurred(), "network policy manifest %s not deleted", workloads[i])
+		}
+	}
+}
+
+// assertNetpolCase curls the netpol service of the case from every client pod, one per node,
+// until the result is the expected one or the command timeout is reached, returning the nodes checked.
+func assertNetpolCase(c netpolCase) ([]string, error) {
+	pods, err := kube.GetPods(c.from, "k8s-app=netpol-client")
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, shared.ReturnLogError("no client pods found in %s", c.from)
+	}
+
+	service := "netpol-client"
+	if c.to == netpolServer {
+		service = "netpol-server"
+	}
+
+	expected := netpolResult(c.reachable)
+	var nodes []string
+	for _, pod := range pods {
+		cmd := fmt.Sprintf("kubectl exec -n %s %s --kubeconfig=%s -- sh -c "+
+			"'curl -sf -m 5 -o /dev/null http://%s.%s:8080/name.html && echo reachable || echo blocked'",
+			c.from, pod.Name, shared.KubeConfigFile, service, c.to)
+		if err = assert.ValidateOnHost(cmd, expected); err != nil {
+			return nil, shared.ReturnLogError("%s to %s from %s should be %s: %w",
+				c.from, c.to, pod.Node, expected, err)
+		}
+		nodes = append(nodes, pod.Node)
+	}
+
+	return nodes, nil
+}
+
+func netpolResult(reachable bool) string {
+	if reachable {
+		return "reachable"
+	}
+
+	return "blocked"
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/rancher/distros-test-framework/factory"
 	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -17,6 +18,9 @@ func TestNodeStatus(
 	nodeAssertVersion assert.NodeAssertFunc,
 ) {
 	cluster := factory.AddCluster(GinkgoT())
+	cfg, err := shared.GetConfig()
+	Expect(err).NotTo(HaveOccurred())
+
 	expectedNodeCount := cluster.NumServers + cluster.NumAgents
 
 	if cluster.Config.Product == "rke2" {
@@ -24,7 +28,7 @@ func TestNodeStatus(
 	}
 
 	Eventually(func(g Gomega) {
-		nodes, err := shared.GetNodes(false)
+		nodes, err := kube.GetNodes()
 		g.Expect(err).NotTo(HaveOccurred())
 		g.Expect(len(nodes)).To(Equal(expectedNodeCount),
 			"Number of nodes should match the spec")
@@ -36,9 +40,9 @@ func TestNodeStatus(
 				nodeAssertVersion(g, node)
 			}
 		}
-	}, "1500s", "20s").Should(Succeed())
+	}, cfg.Timeouts.Node, "20s").Should(Succeed())
 
 	fmt.Println("\n\nCluster nodes:")
-	_, err := shared.GetNodes(true)
+	_, err = shared.GetNodes(true)
 	Expect(err).NotTo(HaveOccurred())
 }
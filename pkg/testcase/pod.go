@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/gomega"
@@ -18,23 +19,26 @@ func TestPodStatus(
 	podAssertReady assert.PodAssertFunc,
 	podAssertStatus assert.PodAssertFunc,
 ) {
+	cfg, err := shared.GetConfig()
+	Expect(err).NotTo(HaveOccurred())
+
 	Eventually(func(g Gomega) {
-		pods, err := shared.GetPods(false)
+		pods, err := kube.GetPods("", "")
 		g.Expect(err).NotTo(HaveOccurred())
 
 		for _, pod := range pods {
 			processPodStatus(g, pod, podAssertRestarts, podAssertReady, podAssertStatus)
 		}
-	}, "900s", "5s").Should(Succeed())
+	}, cfg.Timeouts.Pod, "5s").Should(Succeed())
 
 	fmt.Println("\n\nCluster Pods:")
-	_, err := shared.GetPods(true)
+	_, err = shared.GetPods(true)
 	Expect(err).NotTo(HaveOccurred())
 }
 
 func processPodStatus(
 	g Gomega,
-	pod shared.Pod,
+	pod kube.Pod,
 	podAssertRestarts, podAssertReady, podAssertStatus assert.PodAssertFunc,
 ) {
 	if strings.Contains(pod.Name, "helm-install") {
@@ -0,0 +1,179 @@
+package testcase
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	psaAccept = "accept"
+	psaReject = "reject"
+	psaWarn   = "warn"
+)
+
+// psaPods are the pods submitted to each namespace, compliant with restricted or violating one of the levels.
+var psaPods = map[string]string{
+	"compliant": `
+    securityContext:
+      runAsNonRoot: true
+      runAsUser: 1000
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: ["ALL"]
+      seccompProfile:
+        type: RuntimeDefault`,
+	"privileged": `
+    securityContext:
+      privileged: true`,
+	"hostpath": `
+    volumeMounts:
+    - name: host
+      mountPath: /host
+  volumes:
+  - name: host
+    hostPath:
+      path: /tmp`,
+	"runasroot": `
+    securityContext:
+      runAsNonRoot: false
+      runAsUser: 0
+      allowPrivilegeEscalation: false
+      capabilities:
+        drop: ["ALL"]
+      seccompProfile:
+        type: RuntimeDefault`,
+}
+
+var psaPodOrder = []string{"compliant", "privileged", "hostpath", "runasroot"}
+
+// psaLevels is the admission of each pod expected by the enforce level,
+// psa-baseline also warns on restricted.
+var psaLevels = map[string]map[string]string{
+	"privileged": {
+		"compliant": psaAccept, "privileged": psaAccept, "hostpath": psaAccept, "runasroot": psaAccept,
+	},
+	"baseline": {
+		"compliant": psaAccept, "privileged": psaReject, "hostpath": psaReject, "runasroot": psaWarn,
+	},
+	"restricted": {
+		"compliant": psaAccept, "privileged": psaReject, "hostpath": psaReject, "runasroot": psaReject,
+	},
+}
+
+// TestPodSecurityAdmission submits compliant and non-compliant pods with a server side dry run to namespaces
+// at each PodSecurity level, to an unlabeled namespace that gets the default of the cluster admission config
+// and to kube-system, exempted on hardened clusters, asserting each pod is accepted, rejected or warned.
+func TestPodSecurityAdmission(deleteWorkload bool) {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for pod security admission")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	defaultLevel, err := defaultPSALevel(product, cluster.ServerIPs[0])
+	Expect(err).NotTo(HaveOccurred(), err)
+	fmt.Printf("\nDefault pod security level: %s\n", defaultLevel)
+
+	_, err = shared.ManageWorkload("apply", "psa.yaml")
+	Expect(err).NotTo(HaveOccurred(), "pod security namespaces not deployed")
+
+	dir, err := os.MkdirTemp("", "psa")
+	Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	namespaces := []string{"psa-privileged", "psa-baseline", "psa-restricted", "psa-default", "kube-system"}
+	expected := map[string]map[string]string{
+		"psa-privileged": psaLevels["privileged"],
+		"psa-baseline":   psaLevels["baseline"],
+		"psa-restricted": psaLevels["restricted"],
+		"psa-default":    psaLevels[defaultLevel],
+		"kube-system":    psaLevels["privileged"],
+	}
+
+	table := fmt.Sprintf("\n%-12s", "POD")
+	for _, ns := range namespaces {
+		table += fmt.Sprintf("%-18s", ns)
+	}
+	table += "\n"
+
+	var mismatches []string
+	for _, pod := range psaPodOrder {
+		file := filepath.Join(dir, pod+".yaml")
+		manifest := fmt.Sprintf("apiVersion: v1\nkind: Pod\nmetadata:\n  name: psa-%s\nspec:\n  containers:\n"+
+			"  - name: pause\n    image: registry.k8s.io/pause:3.9%s\n", pod, psaPods[pod])
+		err = os.WriteFile(file, []byte(manifest), 0o600)
+		Expect(err).NotTo(HaveOccurred())
+
+		table += fmt.Sprintf("%-12s", pod)
+		for _, ns := range namespaces {
+			result, err := admitPod(ns, file)
+			Expect(err).NotTo(HaveOccurred(), err)
+
+			cell := result
+			if result != expected[ns][pod] {
+				cell += " (!" + expected[ns][pod] + ")"
+				mismatches = append(mismatches, fmt.Sprintf("pod %s in %s: got %s, expected %s",
+					pod, ns, result, expected[ns][pod]))
+			}
+			table += fmt.Sprintf("%-18s", cell)
+		}
+		table += "\n"
+	}
+	fmt.Print(table)
+
+	Expect(mismatches).To(BeEmpty(), "unexpected pod security admission:\n%s", strings.Join(mismatches, "\n"))
+
+	if deleteWorkload {
+		_, err = shared.ManageWorkload("delete", "psa.yaml")
+		Expect(err).NotTo(HaveOccurred(), "pod security namespaces not deleted")
+	}
+}
+
+// admitPod submits the pod file to the namespace with a server side dry run and returns whether
+// admission accepted it, rejected it or accepted it with a PodSecurity warning.
+func admitPod(namespace, file string) (string, error) {
+	cmd := "kubectl apply --dry-run=server -n " + namespace + " -f " + file +
+		" --kubeconfig=" + shared.KubeConfigFile
+	res, err := shared.HostExecutor{}.Run(cmd)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case !res.Success() && strings.Contains(res.Stderr, "violates PodSecurity"):
+		return psaReject, nil
+	case !res.Success():
+		return "", shared.ReturnLogError("failed to submit %s to %s: %w", file, namespace, res.Err())
+	case strings.Contains(res.Stderr, "would violate PodSecurity"):
+		return psaWarn, nil
+	default:
+		return psaAccept, nil
+	}
+}
+
+// defaultPSALevel returns the enforce level of namespaces without labels, restricted when the cluster
+// runs the rke2 cis profile or the k3s hardening admission config, privileged otherwise.
+func defaultPSALevel(product, ip string) (string, error) {
+	res, err := shared.RunCommandOnNode(fmt.Sprintf("sudo cat /etc/rancher/%s/config.yaml", product), ip)
+	if err != nil {
+		return "", shared.ReturnLogError("failed to read the %s config on %s: %w", product, ip, err)
+	}
+
+	hardened := strings.Contains(res, "admission-control-config-file")
+	if product == "rke2" {
+		hardened = strings.Contains(res, "profile: cis")
+	}
+	if hardened {
+		return "restricted", nil
+	}
+
+	return "privileged", nil
+}
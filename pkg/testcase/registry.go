@@ -0,0 +1,251 @@
+package testcase
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	registryNamespace = "test-registry-mirror"
+	registryNodePort  = "30500"
+	registryUser      = "distros-test"
+	registryNodeDir   = "/tmp/distros-registry"
+)
+
+// TestRegistryMirror runs a pull-through registry with TLS and basic auth as a pod, points docker.io
+// to it with a rewrite on every node registries.yaml and asserts containerd pulls through the mirror.
+func TestRegistryMirror(deleteWorkload bool) {
+	cluster := factory.AddCluster(GinkgoT())
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	nodes, err := kube.GetNodes()
+	Expect(err).NotTo(HaveOccurred(), err)
+	Expect(nodes).NotTo(BeEmpty(), "no nodes found for the registry mirror")
+	endpoint := net.JoinHostPort(nodes[0].InternalIP, registryNodePort)
+
+	_, err = shared.ManageWorkload("apply", "registry-mirror.yaml")
+	Expect(err).NotTo(HaveOccurred(), "registry mirror manifest not deployed")
+
+	dir, err := os.MkdirTemp("", "registry-mirror")
+	Expect(err).NotTo(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	err = writeRegistryFiles(dir, nodes[0].InternalIP, product, endpoint)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	createSecret := fmt.Sprintf("kubectl create secret generic registry-mirror-certs -n %s "+
+		"--from-file=%s --from-file=%s --from-file=%s --dry-run=client -o yaml --kubeconfig=%s "+
+		"| kubectl apply -f - --kubeconfig=%s", registryNamespace, filepath.Join(dir, "tls.crt"),
+		filepath.Join(dir, "tls.key"), filepath.Join(dir, "htpasswd"), shared.KubeConfigFile, shared.KubeConfigFile)
+	_, err = shared.RunCommandHost(createSecret)
+	Expect(err).NotTo(HaveOccurred(), "registry mirror secret not created: %v", err)
+
+	getRegistry := "kubectl get pods -n " + registryNamespace + " -l app=registry-mirror --kubeconfig="
+	err = assert.CheckComponentCmdHost(getRegistry+shared.KubeConfigFile, "registry-mirror", statusRunning)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	ips := append(append([]string{}, cluster.ServerIPs...), cluster.AgentIPs...)
+
+	// restores the nodes written so far when an assertion fails, so they are not left on a dead mirror.
+	var written []string
+	succeeded := false
+	defer func() {
+		if succeeded {
+			return
+		}
+		if restoreErr := restoreRegistriesConfig(product, written); restoreErr != nil {
+			shared.LogLevel("error", "registries.yaml not restored after failure: %v", restoreErr)
+		}
+	}()
+
+	for _, ip := range ips {
+		fmt.Printf("\nWriting registries.yaml with mirror %s on: %s\n", endpoint, ip)
+		err = writeRegistriesConfig(product, dir, ip)
+		Expect(err).NotTo(HaveOccurred(), err)
+		written = append(written, ip)
+
+		err = shared.RestartCluster(product, ip)
+		Expect(err).NotTo(HaveOccurred(), err)
+	}
+	TestNodeStatus(assert.NodeAssertReadyStatus(), nil)
+
+	// containerd hosts.toml is generated from registries.yaml on start, so it shows the new config was loaded.
+	hostsToml := fmt.Sprintf("sudo cat /var/lib/rancher/%s/agent/etc/containerd/certs.d/docker.io/hosts.toml",
+		product)
+	loaded := fmt.Sprintf("allOf(%s, registry-mirror-ca.crt, rewrite)", endpoint)
+	for _, ip := range ips {
+		err = assert.ValidateOnNode(ip, hostsToml, loaded)
+		Expect(err).NotTo(HaveOccurred(), "containerd hosts.toml on %s does not use the mirror: %v", ip, err)
+	}
+
+	_, err = shared.ManageWorkload("apply", "registry-mirror-client.yaml")
+	Expect(err).NotTo(HaveOccurred(), "registry mirror client manifest not deployed")
+
+	kubeconfigFlag := " --kubeconfig=" + shared.KubeConfigFile
+	getClient := "kubectl get pods -n " + registryNamespace + " registry-mirror-client" + kubeconfigFlag
+	err = assert.CheckComponentCmdHost(getClient, "registry-mirror-client", statusRunning)
+	Expect(err).NotTo(HaveOccurred(), err)
+
+	getEvents := "kubectl get events -n " + registryNamespace +
+		" --field-selector involvedObject.name=registry-mirror-client" + kubeconfigFlag
+	registryLogs := "kubectl logs deployment/registry-mirror -n " + registryNamespace + kubeconfigFlag
+	err = assert.ValidateOnHost(
+		getEvents, "Successfully pulled image",
+		registryLogs, "library/busybox",
+	)
+	Expect(err).NotTo(HaveOccurred(), "image was not pulled through the mirror: %v", err)
+
+	succeeded = true
+	if deleteWorkload {
+		err = restoreRegistriesConfig(product, written)
+		Expect(err).NotTo(HaveOccurred(), err)
+
+		_, err = shared.ManageWorkload("delete", "registry-mirror-client.yaml", "registry-mirror.yaml")
+		Expect(err).NotTo(HaveOccurred(), "registry mirror manifests not deleted")
+	}
+}
+
+// writeRegistryFiles writes the self-signed certificate, the htpasswd and registries.yaml
+// of the mirror into dir, with a generated password.
+func writeRegistryFiles(dir, ip, product, endpoint string) error {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return shared.ReturnLogError("failed to generate registry key: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: "registry-mirror"},
+		IPAddresses:           []net.IP{net.ParseIP(ip)},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return shared.ReturnLogError("failed to create registry certificate: %w", err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return shared.ReturnLogError("failed to marshal registry key: %w", err)
+	}
+
+	secret := make([]byte, 16)
+	if _, err = rand.Read(secret); err != nil {
+		return shared.ReturnLogError("failed to generate registry password: %w", err)
+	}
+	password := hex.EncodeToString(secret)
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return shared.ReturnLogError("failed to hash registry password: %w", err)
+	}
+
+	registries := fmt.Sprintf(`mirrors:
+  docker.io:
+    endpoint:
+      - "https://%[1]s"
+    rewrite:
+      "^distros-test/(.*)": "library/$1"
+configs:
+  "%[1]s":
+    auth:
+      username: %[2]s
+      password: %[3]s
+    tls:
+      ca_file: /etc/rancher/%[4]s/registry-mirror-ca.crt
+`, endpoint, registryUser, password, product)
+
+	files := map[string][]byte{
+		"tls.crt":         pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}),
+		"tls.key":         pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+		"htpasswd":        []byte(registryUser + ":" + string(hash) + "\n"),
+		"registries.yaml": []byte(registries),
+	}
+	for name, content := range files {
+		if err = os.WriteFile(filepath.Join(dir, name), content, 0o600); err != nil {
+			return shared.ReturnLogError("failed to write %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// writeRegistriesConfig copies registries.yaml and the mirror CA to the node,
+// keeping a backup of the registries.yaml already there.
+func writeRegistriesConfig(product, dir, ip string) error {
+	files := []string{filepath.Join(dir, "registries.yaml"), filepath.Join(dir, "tls.crt")}
+	if err := shared.CopyToNode(ip, registryNodeDir, files...); err != nil {
+		return err
+	}
+
+	configDir := "/etc/rancher/" + product
+	cmd := fmt.Sprintf("sudo mkdir -p %[1]s && "+
+		"if [ -f %[1]s/registries.yaml ] && [ ! -f %[1]s/registries.yaml.bak ]; then "+
+		"sudo cp %[1]s/registries.yaml %[1]s/registries.yaml.bak; fi && "+
+		"sudo install -m 600 %[2]s/registries.yaml %[1]s/registries.yaml && "+
+		"sudo install -m 644 %[2]s/tls.crt %[1]s/registry-mirror-ca.crt && sudo rm -rf %[2]s",
+		configDir, registryNodeDir)
+	if _, err := shared.RunCommandOnNode(cmd, ip); err != nil {
+		return shared.ReturnLogError("failed to write registries.yaml on %s: %w", ip, err)
+	}
+
+	return nil
+}
+
+// restoreRegistriesConfig restores the registries.yaml of the nodes and restarts them, trying every node.
+func restoreRegistriesConfig(product string, ips []string) error {
+	var errs []error
+	for _, ip := range ips {
+		if err := removeRegistriesConfig(product, ip); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if err := shared.RestartCluster(product, ip); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// removeRegistriesConfig restores the registries.yaml the node had before the test, if any.
+func removeRegistriesConfig(product, ip string) error {
+	configDir := "/etc/rancher/" + product
+	cmd := fmt.Sprintf("sudo rm -f %[1]s/registry-mirror-ca.crt && "+
+		"if [ -f %[1]s/registries.yaml.bak ]; then sudo mv %[1]s/registries.yaml.bak %[1]s/registries.yaml; "+
+		"else sudo rm -f %[1]s/registries.yaml; fi", configDir)
+	if _, err := shared.RunCommandOnNode(cmd, ip); err != nil {
+		return shared.ReturnLogError("failed to remove registries.yaml on %s: %w", ip, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,178 @@
+package testcase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-version"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/pkg/report"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+)
+
+// TestUpgradeRollback records the version the cluster is running, upgrades it manually to version
+// and reinstalls the recorded version, restoring an etcd snapshot taken before the upgrade when
+// rolling back across minor versions, then asserts the nodes and pods are back on the previous version.
+func TestUpgradeRollback(version string) error {
+	if !strings.HasPrefix(version, "v") {
+		return shared.ReturnLogError("rollback requires a version to upgrade to, got: %s", version)
+	}
+	cluster := factory.AddCluster(GinkgoT())
+
+	previous, err := clusterVersion()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("\nRecorded version before upgrade: %s\n", previous)
+	report.AddProperty("rollback_version", previous)
+
+	product, err := shared.GetProduct()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	restore, err := needsSnapshotRestore(previous, version, cluster.Config.Backend())
+	if err != nil {
+		return err
+	}
+
+	var snapshotPath string
+	if restore {
+		servers, err := etcdFirst(cluster.ServerIPs)
+		if err != nil {
+			return err
+		}
+
+		snapshotPath, err = saveEtcdSnapshot(product, servers[0])
+		if err != nil {
+			return err
+		}
+	}
+
+	if err = TestUpgradeClusterManually(version); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nRolling back cluster to: %s\n", previous)
+	if restore {
+		err = rollbackWithRestore(cluster, product, previous, snapshotPath)
+	} else {
+		err = rolloutUpgrade(cluster, previous, cfg.Upgrade.BatchSize)
+	}
+	if err != nil {
+		return shared.ReturnLogError("rollback to %s failed: %w", previous, err)
+	}
+
+	TestNodeStatus(assert.NodeAssertReadyStatus(), assert.NodeAssertUpgradedTo(previous))
+	TestPodStatus(assert.PodAssertRestart(), assert.PodAssertReady(), assert.PodAssertStatus())
+
+	return nil
+}
+
+// clusterVersion returns the version all nodes are running, failing when they differ.
+func clusterVersion() (string, error) {
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return "", err
+	}
+	if len(nodes) == 0 {
+		return "", shared.ReturnLogError("no nodes found to record the version")
+	}
+
+	for _, n := range nodes[1:] {
+		if n.Version != nodes[0].Version {
+			return "", shared.ReturnLogError("nodes are on different versions: %s on %s, %s on %s",
+				nodes[0].Version, nodes[0].Name, n.Version, n.Name)
+		}
+	}
+
+	return nodes[0].Version, nil
+}
+
+// needsSnapshotRestore reports whether rolling back from upgraded to previous has to restore
+// the etcd datastore, which is the case when going back to a lower minor version on embedded etcd.
+func needsSnapshotRestore(previous, upgraded, dataStore string) (bool, error) {
+	if dataStore != "etcd" {
+		return false, nil
+	}
+
+	from, err := version.NewVersion(upgraded)
+	if err != nil {
+		return false, shared.ReturnLogError("invalid upgrade version %s: %w", upgraded, err)
+	}
+
+	to, err := version.NewVersion(previous)
+	if err != nil {
+		return false, shared.ReturnLogError("invalid previous version %s: %w", previous, err)
+	}
+
+	return from.Segments()[1] != to.Segments()[1], nil
+}
+
+// rollbackWithRestore reinstalls the previous version on the stopped servers, restores the snapshot
+// taken before the upgrade, then reinstalls the agents.
+func rollbackWithRestore(cluster *factory.Cluster, product, previous, snapshotPath string) error {
+	service := serverService(product)
+	skipStart := fmt.Sprintf("sudo INSTALL_%s_SKIP_START=true ", strings.ToUpper(product))
+
+	for _, ip := range cluster.ServerIPs {
+		installCmd, err := nodeInstallCmd(product, previous, "server", ip)
+		if err != nil {
+			return err
+		}
+		installCmd = strings.Replace(installCmd, "sudo ", skipStart, 1)
+
+		fmt.Println("Reinstalling server " + ip + " with: " + installCmd)
+		if _, err := shared.RunCommandOnNode("sudo systemctl stop "+service, ip); err != nil {
+			return shared.ReturnLogError("failed to stop %s on %s: %w", service, ip, err)
+		}
+		if _, err := shared.RunCommandOnNode(installCmd, ip); err != nil {
+			return shared.ReturnLogError("failed to reinstall server %s: %w", ip, err)
+		}
+	}
+
+	servers, err := etcdFirst(cluster.ServerIPs)
+	if err != nil {
+		return err
+	}
+
+	if err = restoreEtcdSnapshot(product, snapshotPath, servers); err != nil {
+		return err
+	}
+
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeouts.Node)
+	if err != nil {
+		return shared.ReturnLogError("invalid node timeout: %w", err)
+	}
+
+	if err = waitNodesUpgraded(cluster.ServerIPs, previous, nil, timeout); err != nil {
+		return err
+	}
+
+	if len(cluster.AgentIPs) == 0 {
+		return nil
+	}
+
+	agents := rolloutBatch{role: "agent", nodeType: "agent", ips: cluster.AgentIPs}
+	if err = upgradeBatch(product, previous, agents); err != nil {
+		return err
+	}
+
+	return waitNodesUpgraded(cluster.AgentIPs, previous, nil, timeout)
+}
@@ -0,0 +1,281 @@
+package testcase
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/shared"
+)
+
+const (
+	etcdRoleLabel         = "node-role.kubernetes.io/etcd"
+	controlPlaneRoleLabel = "node-role.kubernetes.io/control-plane"
+)
+
+// rolloutBatch is a group of nodes of the same role upgraded together.
+type rolloutBatch struct {
+	role     string
+	nodeType string
+	ips      []string
+}
+
+// rolloutUpgrade upgrades the cluster in etcd, control-plane then agent order, batchSize nodes at a time,
+// waiting for each batch to be Ready on the new version before moving to the next one.
+func rolloutUpgrade(cluster *factory.Cluster, version string, batchSize int) error {
+	product, err := shared.GetProduct()
+	if err != nil {
+		return err
+	}
+
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeouts.Node)
+	if err != nil {
+		return shared.ReturnLogError("invalid node timeout: %w", err)
+	}
+
+	batches, err := rolloutPlan(cluster, batchSize)
+	if err != nil {
+		return err
+	}
+
+	for i, batch := range batches {
+		fmt.Printf("\nUpgrading batch %d/%d of %s nodes: %s\n", i+1, len(batches), batch.role,
+			strings.Join(batch.ips, ", "))
+
+		before, err := nodeVersions(batch.ips)
+		if err != nil {
+			return err
+		}
+
+		if err = upgradeBatch(product, version, batch); err != nil {
+			return err
+		}
+
+		if err = waitNodesUpgraded(batch.ips, version, before, timeout); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// rolloutPlan splits the servers by role, etcd first then control-plane, followed by the agents,
+// in batches of batchSize nodes.
+func rolloutPlan(cluster *factory.Cluster, batchSize int) ([]rolloutBatch, error) {
+	if batchSize < 1 {
+		batchSize = 1
+	}
+
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	byIP := make(map[string]kube.Node, len(nodes))
+	for _, n := range nodes {
+		byIP[n.ExternalIP] = n
+		byIP[n.InternalIP] = n
+	}
+
+	servers := append([]string{}, cluster.ServerIPs...)
+	sort.SliceStable(servers, func(i, j int) bool {
+		return serverRank(byIP[servers[i]]) < serverRank(byIP[servers[j]])
+	})
+
+	groups := map[string][]string{}
+	for _, ip := range servers {
+		role := serverRole(byIP[ip])
+		groups[role] = append(groups[role], ip)
+	}
+	groups["agent"] = cluster.AgentIPs
+
+	var batches []rolloutBatch
+	for _, role := range []string{"etcd", "control-plane", "server", "agent"} {
+		nodeType := "server"
+		if role == "agent" {
+			nodeType = "agent"
+		}
+
+		ips := groups[role]
+		for start := 0; start < len(ips); start += batchSize {
+			end := start + batchSize
+			if end > len(ips) {
+				end = len(ips)
+			}
+			batches = append(batches, rolloutBatch{role: role, nodeType: nodeType, ips: ips[start:end]})
+		}
+	}
+
+	return batches, nil
+}
+
+func serverRole(n kube.Node) string {
+	if _, ok := n.Labels[etcdRoleLabel]; ok {
+		return "etcd"
+	}
+	if _, ok := n.Labels[controlPlaneRoleLabel]; ok {
+		return "control-plane"
+	}
+
+	return "server"
+}
+
+func serverRank(n kube.Node) int {
+	switch serverRole(n) {
+	case "etcd":
+		return 0
+	case "control-plane":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// upgradeBatch runs the install command and restarts the service on the batch nodes,
+// returning an error per node that failed.
+func upgradeBatch(product, version string, batch rolloutBatch) error {
+	restartCmd := "sudo systemctl restart " + productService(product, batch.nodeType)
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(batch.ips))
+
+	for i, ip := range batch.ips {
+		wg.Add(1)
+		go func(i int, ip string) {
+			defer wg.Done()
+
+			installCmd, err := nodeInstallCmd(product, version, batch.nodeType, ip)
+			if err != nil {
+				errs[i] = fmt.Errorf("%s node %s: %w", batch.role, ip, err)
+				return
+			}
+
+			fmt.Println("Upgrading " + batch.nodeType + " " + ip + " to: " + installCmd)
+			if _, err = shared.RunCommandOnNode(installCmd, ip); err != nil {
+				errs[i] = fmt.Errorf("%s node %s: install failed: %w", batch.role, ip, err)
+				return
+			}
+
+			fmt.Println("Restarting " + batch.nodeType + ": " + ip)
+			if _, err = shared.RunCommandOnNode(restartCmd, ip); err != nil {
+				errs[i] = fmt.Errorf("%s node %s: restart failed: %w", batch.role, ip, err)
+			}
+		}(i, ip)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return shared.ReturnLogError("upgrade aborted: %w", err)
+	}
+
+	return nil
+}
+
+// waitNodesUpgraded waits for the nodes to be Ready, and on the new version when upgrading to a version.
+//
+// Commits have no version to wait for, so each node has to report a kubelet version other than
+// the one in before, or one with the commit hash, to not pass on the Ready status of the old kubelet.
+func waitNodesUpgraded(ips []string, version string, before map[string]string, timeout time.Duration) error {
+	expectedVersion, commit := "", ""
+	if strings.HasPrefix(version, "v") {
+		expectedVersion = strings.Split(version, "-")[0]
+	} else {
+		commit = version
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		pending := pendingNodes(ips, expectedVersion, commit, before)
+		if len(pending) == 0 {
+			return nil
+		}
+
+		if time.Now().After(deadline) {
+			return shared.ReturnLogError("upgrade aborted, nodes not ready on %s after %s:\n%s",
+				version, timeout, strings.Join(pending, "\n"))
+		}
+
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// nodeVersions returns the kubelet version of each node by ip.
+func nodeVersions(ips []string) (map[string]string, error) {
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return nil, err
+	}
+
+	versions := make(map[string]string, len(ips))
+	for _, n := range nodes {
+		for _, ip := range ips {
+			if ip == n.ExternalIP || ip == n.InternalIP {
+				versions[ip] = n.Version
+			}
+		}
+	}
+
+	return versions, nil
+}
+
+// pendingNodes returns a description of each node not yet Ready on the expected version,
+// or still on its version from before when upgrading to a commit.
+func pendingNodes(ips []string, expectedVersion, commit string, before map[string]string) []string {
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	byIP := make(map[string]kube.Node, len(nodes))
+	for _, n := range nodes {
+		byIP[n.ExternalIP] = n
+		byIP[n.InternalIP] = n
+	}
+
+	var pending []string
+	for _, ip := range ips {
+		n, ok := byIP[ip]
+		switch {
+		case !ok:
+			pending = append(pending, fmt.Sprintf("node %s: not found", ip))
+		case n.Status != "Ready":
+			pending = append(pending, fmt.Sprintf("node %s (%s): status %s", ip, n.Name, n.Status))
+		case expectedVersion != "" && !strings.Contains(n.Version, expectedVersion):
+			pending = append(pending, fmt.Sprintf("node %s (%s): version %s, expected %s",
+				ip, n.Name, n.Version, expectedVersion))
+		case commit != "" && n.Version == before[ip] && !strings.Contains(n.Version, shortCommit(commit)):
+			pending = append(pending, fmt.Sprintf("node %s (%s): version %s unchanged, expected commit %s",
+				ip, n.Name, n.Version, commit))
+		}
+	}
+
+	return pending
+}
+
+// shortCommit returns the commit hash as embedded in the product version of commit builds.
+func shortCommit(commit string) string {
+	if len(commit) > 8 {
+		return commit[:8]
+	}
+
+	return commit
+}
+
+// productService returns the systemd service name of the product for the node type.
+func productService(product, nodeType string) string {
+	if nodeType == "agent" {
+		return product + "-agent"
+	}
+
+	return serverService(product)
+}
@@ -0,0 +1,140 @@
+package testcase
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/rancher/distros-test-framework/factory"
+	"github.com/rancher/distros-test-framework/pkg/assert"
+	"github.com/rancher/distros-test-framework/pkg/report"
+	"github.com/rancher/distros-test-framework/shared"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var manifestSecrets = map[string]string{
+	"e2e-secret1": "hello",
+	"e2e-secret2": "good",
+	"e2e-secret3": "top-secret",
+	"e2e-secret4": "lock",
+	"e2e-secret5": "last",
+}
+
+// encryptionStage is a secrets-encrypt command and the rotation stage the servers report after it.
+type encryptionStage struct {
+	cmd      string
+	expected string
+}
+
+// TestSecretsEncryption creates secrets, checks they are encrypted in etcd, then runs the
+// prepare, rotate and reencrypt stages and rotate-keys when supported, restarting the servers
+// in order after each one, asserting the old and new secrets stay readable and encrypted.
+func TestSecretsEncryption(deleteWorkload bool) {
+	cluster := factory.AddCluster(GinkgoT())
+	Expect(cluster.ServerIPs).NotTo(BeEmpty(), "no servers found for secrets encryption")
+
+	product, err := shared.GetProduct()
+	Expect(err).NotTo(HaveOccurred())
+
+	firstServer := cluster.ServerIPs[0]
+	statusCmd := fmt.Sprintf("sudo %s secrets-encrypt status", product)
+	status, err := shared.RunCommandOnNode(statusCmd, firstServer)
+	Expect(err).NotTo(HaveOccurred(), "failed to get secrets encryption status: %v", err)
+	if !strings.Contains(status, "Encryption Status: Enabled") {
+		// test cases share the version bump spec, skipping it would skip the test cases after this one.
+		shared.LogLevel("warn", "skipping secrets encryption, not enabled on the cluster, "+
+			"provision it with secrets-encryption: true:\n%s", status)
+		report.AddProperty("secrets_encryption", "skipped, not enabled")
+		return
+	}
+
+	_, err = shared.ManageWorkload("apply", "secrets.yaml")
+	Expect(err).NotTo(HaveOccurred(), "secrets manifest not deployed")
+
+	readEtcd := cluster.Config.Backend() == "etcd"
+	secrets := map[string]string{}
+	for name, value := range manifestSecrets {
+		secrets[name] = value
+	}
+	assertSecrets(product, firstServer, secrets, readEtcd)
+
+	stages := []encryptionStage{
+		{"prepare", "Current Rotation Stage: prepare"},
+		{"rotate", "Current Rotation Stage: rotate"},
+		{"reencrypt", "Current Rotation Stage: reencrypt_finished"},
+	}
+
+	help, err := shared.RunCommandOnNode(fmt.Sprintf("sudo %s secrets-encrypt --help", product), firstServer)
+	Expect(err).NotTo(HaveOccurred(), err)
+	if strings.Contains(help, "rotate-keys") {
+		stages = append(stages, encryptionStage{"rotate-keys", "Current Rotation Stage: reencrypt_finished"})
+	}
+
+	for _, stage := range stages {
+		fmt.Printf("\nRunning secrets-encrypt %s on: %s\n", stage.cmd, firstServer)
+		_, err = shared.RunCommandOnNode(fmt.Sprintf("sudo %s secrets-encrypt %s", product, stage.cmd), firstServer)
+		Expect(err).NotTo(HaveOccurred(), "secrets-encrypt %s failed: %v", stage.cmd, err)
+
+		if stage.cmd != "rotate-keys" {
+			for _, ip := range cluster.ServerIPs {
+				err = shared.RestartCluster(product, ip)
+				Expect(err).NotTo(HaveOccurred(), err)
+			}
+		}
+
+		for _, ip := range cluster.ServerIPs {
+			err = assert.ValidateOnNode(ip, statusCmd, stage.expected)
+			Expect(err).NotTo(HaveOccurred(), "secrets-encrypt %s not applied on %s: %v", stage.cmd, ip, err)
+		}
+		err = assert.ValidateOnNode(firstServer, statusCmd, "All hashes match")
+		Expect(err).NotTo(HaveOccurred(), "server encryption hashes do not match after %s: %v", stage.cmd, err)
+
+		name := "e2e-secret-" + stage.cmd
+		_, err = shared.RunCommandHost("kubectl create secret generic " + name +
+			" --from-literal=config.yaml=" + stage.cmd + " --kubeconfig=" + shared.KubeConfigFile)
+		Expect(err).NotTo(HaveOccurred(), "secret %s not created: %v", name, err)
+		secrets[name] = stage.cmd
+
+		assertSecrets(product, firstServer, secrets, readEtcd)
+	}
+
+	if deleteWorkload {
+		_, err = shared.ManageWorkload("delete", "secrets.yaml")
+		Expect(err).NotTo(HaveOccurred(), "secrets manifest not deleted")
+
+		for _, stage := range stages {
+			_, err = shared.RunCommandHost("kubectl delete secret e2e-secret-" + stage.cmd +
+				" --ignore-not-found --kubeconfig=" + shared.KubeConfigFile)
+			Expect(err).NotTo(HaveOccurred(), err)
+		}
+	}
+}
+
+// assertSecrets asserts the secrets are readable through the api with their value
+// and, when reading etcd, stored encrypted.
+func assertSecrets(product, ip string, secrets map[string]string, readEtcd bool) {
+	for name, value := range secrets {
+		getSecret := "kubectl get secret " + name + " -o jsonpath='{.data.config\\.yaml}' --kubeconfig=" +
+			shared.KubeConfigFile + " | base64 -d"
+		err := assert.ValidateOnHost(getSecret, value)
+		Expect(err).NotTo(HaveOccurred(), "secret %s not readable: %v", name, err)
+
+		if readEtcd {
+			err = assert.ValidateOnNode(ip, etcdValueCmd(product, "/registry/secrets/default/"+name), "k8s:enc:")
+			Expect(err).NotTo(HaveOccurred(), "secret %s not encrypted in etcd: %v", name, err)
+		}
+	}
+}
+
+// etcdValueCmd returns the command that prints the start of the raw etcd value of the key,
+// read from the etcd grpc gateway with the server etcd client certificates.
+func etcdValueCmd(product, key string) string {
+	tlsDir := fmt.Sprintf("/var/lib/rancher/%s/server/tls/etcd", product)
+	body := fmt.Sprintf(`{"key":"%s"}`, base64.StdEncoding.EncodeToString([]byte(key)))
+
+	return fmt.Sprintf("sudo curl -s --cacert %[1]s/server-ca.crt "+
+		"--cert %[1]s/client.crt --key %[1]s/client.key https://127.0.0.1:2379/v3/kv/range -d '%[2]s' "+
+		"| grep -o '\"value\":\"[^\"]*\"' | cut -d'\"' -f4 | base64 -d | head -c 32", tlsDir, body)
+}
@@ -2,13 +2,15 @@ package testcase
 
 import (
 	"fmt"
-	"os"
 	"strings"
-	"sync"
+	"time"
 
 	"github.com/rancher/distros-test-framework/factory"
 	"github.com/rancher/distros-test-framework/pkg/assert"
 	"github.com/rancher/distros-test-framework/pkg/customflag"
+	"github.com/rancher/distros-test-framework/pkg/kube"
+	"github.com/rancher/distros-test-framework/pkg/report"
+	"github.com/rancher/distros-test-framework/pkg/suc"
 	"github.com/rancher/distros-test-framework/shared"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -18,6 +20,7 @@ import (
 // TestUpgradeClusterSUC upgrades cluster using the system-upgrade-controller.
 func TestUpgradeClusterSUC(version string) error {
 	fmt.Printf("\nUpgrading cluster to: %s\n", version)
+	report.AddProperty("upgrade_version", version)
 
 	_, err := shared.ManageWorkload("apply", "suc.yaml")
 	Expect(err).NotTo(HaveOccurred(),
@@ -34,30 +37,44 @@ func TestUpgradeClusterSUC(version string) error {
 	product, err := shared.GetProduct()
 	Expect(err).NotTo(HaveOccurred())
 
-	originalFilePath := shared.BasePath() +
-		fmt.Sprintf("/distros-test-framework/workloads/amd64/%s-upgrade-plan.yaml", product)
-	newFilePath := shared.BasePath() + "/distros-test-framework/workloads/amd64/plan.yaml"
+	timeout, err := sucTimeout()
+	if err != nil {
+		return err
+	}
+
+	plans := suc.NewPlans(suc.DefaultPlanConfig(product, version))
+	appliedAt := time.Now()
+	if err = suc.ApplyPlans(plans); err != nil {
+		return err
+	}
+
+	return suc.WatchPlans(plans, appliedAt, timeout)
+}
 
-	content, err := os.ReadFile(originalFilePath)
+// sucTimeout gives each node of the cluster the node timeout to be upgraded.
+func sucTimeout() (time.Duration, error) {
+	cfg, err := shared.GetConfig()
 	if err != nil {
-		return fmt.Errorf("failed to read file: %s", err)
+		return 0, err
 	}
 
-	newContent := strings.ReplaceAll(string(content), "$UPGRADEVERSION", version)
-	err = os.WriteFile(newFilePath, []byte(newContent), 0644)
+	timeout, err := time.ParseDuration(cfg.Timeouts.Node)
 	if err != nil {
-		return fmt.Errorf("failed to write file: %s", err)
+		return 0, shared.ReturnLogError("invalid node timeout: %w", err)
 	}
 
-	_, err = shared.ManageWorkload("apply", "plan.yaml")
-	Expect(err).NotTo(HaveOccurred(), "failed to upgrade cluster.")
+	nodes, err := kube.GetNodes()
+	if err != nil {
+		return 0, err
+	}
 
-	return nil
+	return timeout * time.Duration(len(nodes)), nil
 }
 
 // TestUpgradeClusterManually upgrades the cluster "manually"
 func TestUpgradeClusterManually(version string) error {
 	fmt.Printf("\nUpgrading cluster to: %s\n", version)
+	report.AddProperty("upgrade_version", version)
 
 	if version == "" {
 		return shared.ReturnLogError("please provide a non-empty version or commit to upgrade to")
@@ -68,55 +85,12 @@ func TestUpgradeClusterManually(version string) error {
 		return shared.ReturnLogError("no nodes found to upgrade")
 	}
 
-	if cluster.NumServers > 0 {
-		if err := upgradeServer(version, cluster.ServerIPs); err != nil {
-			return err
-		}
-	}
-
-	if cluster.NumAgents > 0 {
-		if err := upgradeAgent(version, cluster.AgentIPs); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-// upgradeNode upgrades a node server or agent type to the specified version
-func upgradeNode(nodeType string, installType string, ips []string) error {
-	var wg sync.WaitGroup
-	errCh := make(chan error, len(ips))
-
-	upgradeCommand := getInstallCmd(installType, nodeType)
-
-	for _, ip := range ips {
-		wg.Add(1)
-		go func(ip, upgradeCommand string) {
-			defer wg.Done()
-			defer GinkgoRecover()
-
-			fmt.Println("Upgrading " + nodeType + " to: " + upgradeCommand)
-			if _, err := shared.RunCommandOnNode(upgradeCommand, ip); err != nil {
-				fmt.Printf("\nError upgrading %s %s: %v\n\n", nodeType, ip, err)
-				errCh <- err
-				close(errCh)
-				return
-			}
-
-			product, err := shared.GetProduct()
-			if err != nil {
-				return
-			}
-
-			fmt.Println("Restarting " + nodeType + ": " + ip)
-			shared.RestartCluster(product, ip)
-		}(ip, upgradeCommand)
+	cfg, err := shared.GetConfig()
+	if err != nil {
+		return err
 	}
-	wg.Wait()
-	close(errCh)
 
-	return nil
+	return rolloutUpgrade(cluster, version, cfg.Upgrade.BatchSize)
 }
 
 func getInstallCmd(installType string, nodeType string) string {
@@ -155,11 +129,3 @@ func getChannel() string {
 
 	return defaultChannel
 }
-
-func upgradeServer(installType string, serverIPs []string) error {
-	return upgradeNode("server", installType, serverIPs)
-}
-
-func upgradeAgent(installType string, agentIPs []string) error {
-	return upgradeNode("agent", installType, agentIPs)
-}
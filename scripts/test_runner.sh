@@ -11,7 +11,7 @@ if [ -z "${IMG_NAME}" ]; then
 fi
 
 case "$TEST_DIR" in
-     upgradecluster|versionbump|mixedoscluster|dualstack|validatecluster|createcluster)
+     upgradecluster|versionbump|mixedoscluster|dualstack|cis|datastore|validatecluster|createcluster)
       printf "\n\nRunning tests for %s\n\n" "${TEST_DIR} on ${ENV_PRODUCT}"
         ;;
     *)
@@ -26,22 +26,31 @@ if [ -n "${TEST_DIR}" ]; then
             go test -timeout=45m -v -tags=upgrademanual -count=1 ./entrypoint/upgradecluster/... -installVersionOrCommit "${INSTALL_VERSION_OR_COMMIT}" -channel "${CHANNEL}"
         elif [ "${TEST_TAG}" = "upgradesuc" ]; then
             go test -timeout=45m -v -tags=upgradesuc -count=1 ./entrypoint/upgradecluster/... -sucUpgradeVersion "${SUC_UPGRADE_VERSION}"
+        elif [ "${TEST_TAG}" = "upgraderollback" ]; then
+            go test -timeout=60m -v -tags=upgraderollback -count=1 ./entrypoint/upgradecluster/... -installVersionOrCommit "${INSTALL_VERSION_OR_COMMIT}" -channel "${CHANNEL}"
         fi
     elif [ "${TEST_DIR}" = "versionbump" ]; then
         go test -timeout=45m -v -tags=versionbump -count=1 ./entrypoint/versionbump/... \
             -cmd "${CMD}" \
+            -target "${TARGET}" \
             -expectedValue "${EXPECTED_VALUE}" \
             -expectedValueUpgrade "${VALUE_UPGRADED}" \
             -installVersionOrCommit "${INSTALL_VERSION_OR_COMMIT}" \
             -channel "${CHANNEL}" \
             -testCase "${TEST_CASE}" \
+            -certServices "${CERT_SERVICES}" \
             -deployWorkload "${DEPLOY_WORKLOAD}" \
             -workloadName "${WORKLOAD_NAME}" \
-            -description "${DESCRIPTION}"
+            -description "${DESCRIPTION}" \
+            -plan "${PLAN}"
     elif [ "${TEST_DIR}" = "mixedoscluster" ]; then
         go test -timeout=45m -v -count=1 ./entrypoint/mixedoscluster/... -sonobuoyVersion "${SONOBUOYVERSION}"
     elif [ "${TEST_DIR}" = "dualstack" ]; then
         go test -timeout=45m -v -count=1 ./entrypoint/dualstack/...
+    elif [ "${TEST_DIR}" = "cis" ]; then
+        go test -timeout=60m -v -count=1 ./entrypoint/cis/...
+    elif [ "${TEST_DIR}" = "datastore" ]; then
+        go test -timeout=60m -v -count=1 ./entrypoint/datastore/... -destroy=true
     elif [  "${TEST_DIR}" = "createcluster" ]; then
         go test -timeout=45m -v -count=1 ./entrypoint/createcluster/...
     elif [ "${TEST_DIR}" = "validatecluster" ]; then
@@ -1,81 +1,58 @@
 package shared
 
 import (
-	"bytes"
 	"encoding/base64"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
 	"strings"
 
 	"github.com/rancher/distros-test-framework/config"
 	"github.com/rancher/distros-test-framework/pkg/logger"
-	"golang.org/x/crypto/ssh"
 )
 
-// RunCommandHost executes a command on the host
+// RunCommandHost executes the commands on the host in order and returns the output of the last one.
+//
+// when a command exits with non zero code its stderr is returned with the error.
 func RunCommandHost(cmds ...string) (string, error) {
 	if cmds == nil {
 		return "", ReturnLogError("should send at least one command")
 	}
 
-	var output, errOut bytes.Buffer
+	var res *CmdResult
 	for _, cmd := range cmds {
-		if cmd == "" {
-			return "", ReturnLogError("cmd should not be empty")
-		}
-
-		c := exec.Command("bash", "-c", cmd)
-		c.Stdout = &output
-		c.Stderr = &errOut
-
-		err := c.Run()
+		var err error
+		res, err = HostExecutor{}.Run(cmd)
 		if err != nil {
-			return c.Stderr.(*bytes.Buffer).String(), err
+			return "", err
+		}
+		if !res.Success() {
+			return strings.TrimSpace(res.Stderr), res.Err()
 		}
 	}
 
-	return output.String(), nil
+	return res.Output(), nil
 }
 
-// RunCommandOnNode executes a command on the node SSH
+// RunCommandOnNode executes a command on the node SSH and returns its stdout and stderr combined.
+//
+// success is decided by the exit code, non zero exit codes return an error with stderr,
+// unless stderr asks for a restart, as the product commands do after changing the service config.
 func RunCommandOnNode(cmd, ip string) (string, error) {
-	if cmd == "" {
-		return "", ReturnLogError("cmd should not be empty")
-	}
-
-	host := ip + ":22"
-	conn, err := configureSSH(host)
+	res, err := SSHExecutor{IP: ip}.Run(cmd)
 	if err != nil {
-		return "", ReturnLogError("failed to configure SSH: %v\n", err)
-	}
-	stdout, stderr, err := runsshCommand(cmd, conn)
-	if err != nil && !strings.Contains(stderr, "restart") {
-		return "", fmt.Errorf(
-			"command: %s failed on run ssh: %s with error: %w\n",
-			cmd,
-			ip,
-			err,
-		)
+		return "", err
 	}
-
-	stdout = strings.TrimSpace(stdout)
-	stderr = strings.TrimSpace(stderr)
-
-	cleanedStderr := strings.ReplaceAll(stderr, "\n", "")
-	cleanedStderr = strings.ReplaceAll(cleanedStderr, "\t", "")
-
-	if cleanedStderr != "" && (!strings.Contains(stderr, "exited") ||
-		!strings.Contains(cleanedStderr, "1") ||
-		!strings.Contains(cleanedStderr, "2")) {
-		return cleanedStderr, nil
-	} else if cleanedStderr != "" {
-		return "", fmt.Errorf("command: %s failed with error: %v\n", cmd, stderr)
+	if !res.Success() {
+		LogLevel("warn", "%v\n", res.Err())
+		if strings.Contains(res.Stderr, "restart") {
+			return res.CombinedOutput(), nil
+		}
+		return "", res.Err()
 	}
 
-	return stdout, err
+	return res.CombinedOutput(), nil
 }
 
 // BasePath returns the base path of the project.
@@ -85,7 +62,9 @@ func BasePath() string {
 	return filepath.Join(filepath.Dir(b), "../..")
 }
 
-// EnvDir returns the environment directory of the project based on the package passed.
+// EnvDir returns the config file path of the project based on the package passed.
+//
+// config/config.yaml, config/config.yml and config/config.json are preferred over the legacy config/.env.
 func EnvDir(pkg string) (string, error) {
 	_, callerFilePath, _, ok := runtime.Caller(1)
 	if !ok {
@@ -93,24 +72,32 @@ func EnvDir(pkg string) (string, error) {
 	}
 	callerDir := filepath.Dir(callerFilePath)
 
-	var env string
 	var c string
 
 	switch pkg {
 	case "factory":
 		c = filepath.Dir(filepath.Join(callerDir))
-		env = filepath.Join(c, "config/.env")
 	case "entrypoint":
 		c = filepath.Dir(filepath.Join(callerDir, ".."))
-		env = filepath.Join(c, "config/.env")
 	case ".":
-		c = filepath.Dir(filepath.Join(callerDir))
-		env = filepath.Join(callerDir, "config/.env")
+		c = filepath.Dir(callerDir)
 	default:
 		return "", ReturnLogError("unknown package: %s\n", pkg)
 	}
 
-	return env, nil
+	return configFile(filepath.Join(c, "config")), nil
+}
+
+// configFile returns the first config file found on dir, defaulting to .env.
+func configFile(dir string) string {
+	for _, name := range []string{"config.yaml", "config.yml", "config.json"} {
+		file := filepath.Join(dir, name)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+	}
+
+	return filepath.Join(dir, ".env")
 }
 
 // PrintFileContents prints the contents of the file as [] string.
@@ -166,16 +153,26 @@ func getVersion(cmd string) (string, error) {
 	return res, nil
 }
 
-// GetProduct returns the distro product based on the config file
-func GetProduct() (string, error) {
+// GetConfig returns the run configuration loaded from the config file
+func GetConfig() (*config.ProductConfig, error) {
 	cfgPath, err := EnvDir(".")
 	if err != nil {
-		return "", ReturnLogError("failed to get config path: %v\n", err)
+		return nil, ReturnLogError("failed to get config path: %v\n", err)
 	}
 
 	cfg, err := config.AddConfigEnv(cfgPath)
 	if err != nil {
-		return "", ReturnLogError("failed to get config: %v\n", err)
+		return nil, ReturnLogError("failed to get config: %v\n", err)
+	}
+
+	return cfg, nil
+}
+
+// GetProduct returns the distro product based on the config file
+func GetProduct() (string, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return "", err
 	}
 	if cfg.Product != "k3s" && cfg.Product != "rke2" {
 		return "", ReturnLogError("unknown product")
@@ -207,66 +204,6 @@ func AddHelmRepo(name, url string) (string, error) {
 	return RunCommandHost(addRepo, update, installRepo)
 }
 
-func publicKey(path string) (ssh.AuthMethod, error) {
-	key, err := os.ReadFile(path)
-	if err != nil {
-		return nil, ReturnLogError("failed to read private key: %v", err)
-	}
-	signer, err := ssh.ParsePrivateKey(key)
-	if err != nil {
-		return nil, ReturnLogError("failed to parse private key: %v", err)
-	}
-
-	return ssh.PublicKeys(signer), nil
-}
-
-func configureSSH(host string) (*ssh.Client, error) {
-	var cfg *ssh.ClientConfig
-
-	authMethod, err := publicKey(AccessKey)
-	if err != nil {
-		return nil, ReturnLogError("failed to get public key: %v", err)
-	}
-	cfg = &ssh.ClientConfig{
-		User: AwsUser,
-		Auth: []ssh.AuthMethod{
-			authMethod,
-		},
-		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
-	}
-	conn, err := ssh.Dial("tcp", host, cfg)
-	if err != nil {
-		return nil, ReturnLogError("failed to dial: %v", err)
-	}
-
-	return conn, nil
-}
-
-func runsshCommand(cmd string, conn *ssh.Client) (stdoutStr, stderrStr string, err error) {
-	session, err := conn.NewSession()
-	if err != nil {
-		return "", "", ReturnLogError("failed to create session: %v\n", err)
-	}
-
-	defer session.Close()
-
-	var stdoutBuf bytes.Buffer
-	var stderrBuf bytes.Buffer
-	session.Stdout = &stdoutBuf
-	session.Stderr = &stderrBuf
-
-	errssh := session.Run(cmd)
-	stdoutStr = stdoutBuf.String()
-	stderrStr = stderrBuf.String()
-
-	if errssh != nil {
-		LogLevel("warn", "%v\n", stderrStr)
-		return "", stderrStr, errssh
-	}
-
-	return stdoutStr, stderrStr, nil
-}
-
 // JoinCommands joins the first command with some arg
 func JoinCommands(cmd, kubeconfigFlag string) string {
 	cmds := strings.Split(cmd, ":")
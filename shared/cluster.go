@@ -14,6 +14,7 @@ var (
 	AwsUser        string
 	AccessKey      string
 	Arch           string
+	KnownHostsFile string
 )
 
 type Node struct {
@@ -227,10 +228,78 @@ func FetchNodeExternalIP() []string {
 	return nodeExternalIPs
 }
 
-// RestartCluster restarts the service on each node given by external IP.
-func RestartCluster(product, ip string) {
-	_, _ = RunCommandOnNode(fmt.Sprintf("sudo systemctl restart %s*", product), ip)
-	time.Sleep(20 * time.Second)
+// FetchNodeExternalIPBySelector returns the external IP of the nodes matching the label selector.
+func FetchNodeExternalIPBySelector(selector string) ([]string, error) {
+	res, err := RunCommandHost("kubectl get nodes -l '" + selector + "' " +
+		"--output=jsonpath='{.items[*].status.addresses[?(@.type==\"ExternalIP\")].address}' " +
+		"--kubeconfig=" + KubeConfigFile)
+	if err != nil {
+		return nil, ReturnLogError("failed to fetch nodes by selector %s: %v\n", selector, err)
+	}
+
+	return strings.Fields(res), nil
+}
+
+// RestartCluster restarts the product service on the node given by external IP,
+// waiting until the service is active and the node is Ready again.
+func RestartCluster(product, ip string) error {
+	cfg, err := GetConfig()
+	if err != nil {
+		return err
+	}
+	nodeTimeout, err := time.ParseDuration(cfg.Timeouts.Node)
+	if err != nil {
+		return ReturnLogError("invalid node timeout: %w", err)
+	}
+
+	fmt.Printf("\nRestarting %s on: %s\n", product, ip)
+	if _, err = RunCommandOnNode(fmt.Sprintf("sudo systemctl restart %s*", product), ip); err != nil {
+		return ReturnLogError("failed to restart %s on %s: %w", product, ip, err)
+	}
+
+	timeout := time.After(nodeTimeout)
+	tick := time.NewTicker(5 * time.Second)
+	defer tick.Stop()
+
+	for {
+		select {
+		case <-tick.C:
+			if restartedNodeReady(product, ip) {
+				return nil
+			}
+		case <-timeout:
+			return ReturnLogError("%s on %s not active and Ready after restart in %s", product, ip, nodeTimeout)
+		}
+	}
+}
+
+// restartedNodeReady returns true when every product service on the node is active
+// and the node with the ip reports Ready, errors while the service restarts count as not ready.
+func restartedNodeReady(product, ip string) bool {
+	active, err := SSHExecutor{IP: ip}.Run(fmt.Sprintf("sudo systemctl is-active '%s*'", product))
+	if err != nil || !active.Success() || active.Output() == "" {
+		return false
+	}
+	for _, state := range strings.Fields(active.Output()) {
+		if state != "active" {
+			return false
+		}
+	}
+
+	res, err := RunCommandHost("kubectl get nodes --kubeconfig=" + KubeConfigFile +
+		" -o jsonpath='{range .items[*]}{.status.addresses[*].address} " +
+		"{.status.conditions[?(@.type==\"Ready\")].status}{\"\\n\"}{end}'")
+	if err != nil {
+		return false
+	}
+	for _, line := range strings.Split(res, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 1 && strings.Contains(" "+line+" ", " "+ip+" ") {
+			return fields[len(fields)-1] == "True"
+		}
+	}
+
+	return false
 }
 
 // FetchIngressIP returns the ingress IP of the given namespace
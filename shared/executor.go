@@ -0,0 +1,160 @@
+package shared
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// CmdResult represents the result of a command run on the host or on a node.
+type CmdResult struct {
+	Cmd      string
+	NodeIP   string
+	Stdout   string
+	Stderr   string
+	ExitCode int
+	Duration time.Duration
+}
+
+// Executor runs commands on a destination, host, SSH or future transports.
+//
+// Run returns an error only when the command could not be run, a command that
+// ran and failed is reported by the ExitCode of the result.
+type Executor interface {
+	Run(cmd string) (*CmdResult, error)
+}
+
+// HostExecutor runs commands on the host with bash.
+type HostExecutor struct{}
+
+// SSHExecutor runs commands on the node IP through the pooled ssh connection.
+type SSHExecutor struct {
+	IP string
+}
+
+// NewExecutor returns the HostExecutor for empty or "host" ip, otherwise the SSHExecutor for the ip.
+func NewExecutor(ip string) Executor {
+	if ip == "" || ip == "host" {
+		return HostExecutor{}
+	}
+
+	return SSHExecutor{IP: ip}
+}
+
+// Success returns true when the command exited with code 0.
+func (r *CmdResult) Success() bool {
+	return r.ExitCode == 0
+}
+
+// Output returns the trimmed stdout.
+func (r *CmdResult) Output() string {
+	return strings.TrimSpace(r.Stdout)
+}
+
+// CombinedOutput returns the trimmed stdout followed by the trimmed stderr,
+// for tools printing on stderr like the cni plugins.
+func (r *CmdResult) CombinedOutput() string {
+	stdout, stderr := strings.TrimSpace(r.Stdout), strings.TrimSpace(r.Stderr)
+	if stdout == "" || stderr == "" {
+		return stdout + stderr
+	}
+
+	return stdout + "\n" + stderr
+}
+
+// Err returns nil when the command succeeded, otherwise an error with exit code and stderr.
+func (r *CmdResult) Err() error {
+	if r.Success() {
+		return nil
+	}
+
+	return fmt.Errorf("command: %s on %s exited with code %d: %s",
+		r.Cmd, r.destination(), r.ExitCode, strings.TrimSpace(r.Stderr))
+}
+
+func (r *CmdResult) destination() string {
+	if r.NodeIP == "" {
+		return "host"
+	}
+
+	return r.NodeIP
+}
+
+// Run runs the command on the host.
+func (HostExecutor) Run(cmd string) (*CmdResult, error) {
+	if cmd == "" {
+		return nil, ReturnLogError("cmd should not be empty")
+	}
+
+	var stdout, stderr bytes.Buffer
+	c := exec.Command("bash", "-c", cmd)
+	c.Stdout = &stdout
+	c.Stderr = &stderr
+
+	start := time.Now()
+	err := c.Run()
+	res := &CmdResult{
+		Cmd:      cmd,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	defer recordCommand(res)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitCode()
+	default:
+		return res, ReturnLogError("failed to run command: %s on host: %w", cmd, err)
+	}
+
+	return res, nil
+}
+
+// Run runs the command on the node.
+func (e SSHExecutor) Run(cmd string) (*CmdResult, error) {
+	if cmd == "" {
+		return nil, ReturnLogError("cmd should not be empty")
+	}
+
+	session, release, err := newSession(net.JoinHostPort(e.IP, sshPort))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	start := time.Now()
+	err = session.Run(cmd)
+	res := &CmdResult{
+		Cmd:      cmd,
+		NodeIP:   e.IP,
+		Stdout:   stdout.String(),
+		Stderr:   stderr.String(),
+		Duration: time.Since(start),
+	}
+	defer recordCommand(res)
+
+	var exitErr *ssh.ExitError
+	switch {
+	case err == nil:
+	case errors.As(err, &exitErr):
+		res.ExitCode = exitErr.ExitStatus()
+	default:
+		return res, ReturnLogError("failed to run command: %s on ssh: %s: %w", cmd, e.IP, err)
+	}
+
+	return res, nil
+}
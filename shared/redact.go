@@ -0,0 +1,54 @@
+package shared
+
+import "regexp"
+
+const redacted = "<redacted>"
+
+// redactions are the patterns of tokens and keys removed from the recorded commands and collected files.
+var redactions = []struct {
+	expr    *regexp.Regexp
+	replace string
+}{
+	{
+		expr:    regexp.MustCompile(`(?s)-----BEGIN [A-Z ]*PRIVATE KEY-----.*?-----END [A-Z ]*PRIVATE KEY-----`),
+		replace: redacted,
+	},
+	{
+		expr:    regexp.MustCompile(`K10[0-9a-f]+::[A-Za-z0-9_-]+:[A-Za-z0-9]+`),
+		replace: redacted,
+	},
+	{
+		expr: regexp.MustCompile(`(?i)((?:agent-)?token|password|secret|access[-_]key|secret[-_]key)` +
+			`(["']?\s*[:=]\s*["']?)[^\s"',]+`),
+		replace: "${1}${2}" + redacted,
+	},
+	{
+		expr:    regexp.MustCompile(`(?i)(--(?:agent-)?token[= ])\S+`),
+		replace: "${1}" + redacted,
+	},
+	{
+		expr:    regexp.MustCompile(`(?i)\b([a-z][a-z0-9+.-]*://)[^\s/@:'"]+:[^\s@'"]+@`),
+		replace: "${1}" + redacted + "@",
+	},
+	{
+		expr:    regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`),
+		replace: "${1}" + redacted,
+	},
+	{
+		expr:    regexp.MustCompile(`(client-key-data|client-certificate-data|certificate-authority-data):\s*\S+`),
+		replace: "${1}: " + redacted,
+	},
+	{
+		expr:    regexp.MustCompile(`AKIA[0-9A-Z]{16}`),
+		replace: redacted,
+	},
+}
+
+// Redact removes tokens, passwords and keys from content.
+func Redact(content string) string {
+	for _, r := range redactions {
+		content = r.expr.ReplaceAllString(content, r.replace)
+	}
+
+	return content
+}
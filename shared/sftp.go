@@ -0,0 +1,66 @@
+package shared
+
+import (
+	"io"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/sftp"
+)
+
+// CopyToNode uploads the local files into dir on the node over sftp, reusing the pooled ssh connection.
+//
+// dir is created when missing and the files keep their local permissions.
+func CopyToNode(ip, dir string, files ...string) error {
+	conn, release, err := pool.acquire(net.JoinHostPort(ip, sshPort))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	client, err := sftp.NewClient(conn.client)
+	if err != nil {
+		return ReturnLogError("failed to start sftp on %s: %v", ip, err)
+	}
+	defer client.Close()
+
+	if err = client.MkdirAll(dir); err != nil {
+		return ReturnLogError("failed to create %s on %s: %v", dir, ip, err)
+	}
+
+	for _, file := range files {
+		remotePath := path.Join(dir, filepath.Base(file))
+		if err = copyFile(client, file, remotePath); err != nil {
+			return ReturnLogError("failed to copy %s to %s:%s: %v", file, ip, remotePath, err)
+		}
+	}
+
+	return nil
+}
+
+func copyFile(client *sftp.Client, localPath, remotePath string) error {
+	local, err := os.Open(localPath)
+	if err != nil {
+		return err
+	}
+	defer local.Close()
+
+	info, err := local.Stat()
+	if err != nil {
+		return err
+	}
+
+	remote, err := client.Create(remotePath)
+	if err != nil {
+		return err
+	}
+	defer remote.Close()
+
+	if _, err = io.Copy(remote, local); err != nil {
+		return err
+	}
+
+	return remote.Chmod(info.Mode().Perm())
+}
@@ -0,0 +1,313 @@
+package shared
+
+import (
+	"errors"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	sshPort           = "22"
+	sshDialTimeout    = 30 * time.Second
+	keepAliveInterval = 30 * time.Second
+
+	maxSessionsPerHost = 8
+)
+
+// sshPool keeps one ssh client per host reused by all commands sent to that host.
+type sshPool struct {
+	mu    sync.Mutex
+	conns map[string]*sshConn
+}
+
+// sshConn is the pooled client of a host, ready is closed once the dial finished with client or err set.
+//
+// sessions caps the sessions open at once on the client below the sshd MaxSessions default of 10.
+type sshConn struct {
+	ready    chan struct{}
+	client   *ssh.Client
+	err      error
+	done     chan struct{}
+	sessions chan struct{}
+}
+
+var pool = &sshPool{conns: map[string]*sshConn{}}
+
+// get returns the pooled client for the host, dialing a new one when there is none.
+//
+// the dial runs outside the pool lock so an unreachable host only blocks the callers of that host.
+func (p *sshPool) get(host string) (*sshConn, error) {
+	p.mu.Lock()
+	conn, ok := p.conns[host]
+	if !ok {
+		conn = &sshConn{
+			ready:    make(chan struct{}),
+			done:     make(chan struct{}),
+			sessions: make(chan struct{}, maxSessionsPerHost),
+		}
+		p.conns[host] = conn
+	}
+	p.mu.Unlock()
+
+	if !ok {
+		conn.client, conn.err = configureSSH(host)
+		if conn.err != nil {
+			p.mu.Lock()
+			if p.conns[host] == conn {
+				delete(p.conns, host)
+			}
+			p.mu.Unlock()
+		} else {
+			go p.keepAlive(host, conn)
+		}
+		close(conn.ready)
+	}
+
+	<-conn.ready
+	if conn.err != nil {
+		return nil, conn.err
+	}
+
+	return conn, nil
+}
+
+// acquire returns the pooled client for the host once a session slot is free on it,
+// release must be called when the session is closed.
+func (p *sshPool) acquire(host string) (conn *sshConn, release func(), err error) {
+	conn, err = p.get(host)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	select {
+	case conn.sessions <- struct{}{}:
+	case <-conn.done:
+		return nil, nil, ReturnLogError("ssh connection to %s closed", host)
+	}
+
+	return conn, func() { <-conn.sessions }, nil
+}
+
+// drop closes the client and removes it from the pool if it is still the pooled one.
+func (p *sshPool) drop(host string, conn *sshConn) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.conns[host] == conn {
+		close(conn.done)
+		delete(p.conns, host)
+	}
+	_ = conn.client.Close()
+}
+
+// keepAlive sends keepalive requests until the client is dropped or stops answering.
+func (p *sshPool) keepAlive(host string, conn *sshConn) {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-conn.done:
+			return
+		case <-ticker.C:
+			if !conn.alive() {
+				LogLevel("warn", "ssh keepalive failed on %s, dropping connection", host)
+				p.drop(host, conn)
+				return
+			}
+		}
+	}
+}
+
+// alive reports whether the server still answers on the client.
+func (c *sshConn) alive() bool {
+	_, _, err := c.client.SendRequest("keepalive@openssh.com", true, nil)
+
+	return err == nil
+}
+
+// CloseSSHConnections closes all pooled ssh connections, it should be called at the end of the suite.
+func CloseSSHConnections() {
+	pool.mu.Lock()
+	conns := pool.conns
+	pool.conns = map[string]*sshConn{}
+	pool.mu.Unlock()
+
+	for _, conn := range conns {
+		<-conn.ready
+		if conn.err == nil {
+			close(conn.done)
+			_ = conn.client.Close()
+		}
+	}
+}
+
+// newSession returns a session from the pooled client and the func releasing its slot,
+// reconnecting once when the client is found dead.
+//
+// a session refused on a live client, as when sshd MaxSessions is reached, keeps the client
+// since other sessions are still running on it.
+func newSession(host string) (*ssh.Session, func(), error) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		conn, release, acquireErr := pool.acquire(host)
+		if acquireErr != nil {
+			return nil, nil, acquireErr
+		}
+
+		var session *ssh.Session
+		session, err = conn.client.NewSession()
+		if err == nil {
+			return session, release, nil
+		}
+		release()
+
+		if conn.alive() {
+			break
+		}
+
+		LogLevel("warn", "failed to create session on %s, reconnecting: %v", host, err)
+		pool.drop(host, conn)
+	}
+
+	return nil, nil, ReturnLogError("failed to create session on %s: %v\n", host, err)
+}
+
+func publicKey(path string) (ssh.AuthMethod, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, ReturnLogError("failed to read private key: %v", err)
+	}
+	signer, err := ssh.ParsePrivateKey(key)
+	if err != nil {
+		return nil, ReturnLogError("failed to parse private key: %v", err)
+	}
+
+	return ssh.PublicKeys(signer), nil
+}
+
+// hostKeyCallback pins the host keys from KnownHostsFile when set, otherwise host keys are not checked.
+func hostKeyCallback() (ssh.HostKeyCallback, error) {
+	if KnownHostsFile == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+
+	callback, err := knownhosts.New(KnownHostsFile)
+	if err != nil {
+		return nil, ReturnLogError("failed to load known hosts %s: %v", KnownHostsFile, err)
+	}
+
+	return callback, nil
+}
+
+func configureSSH(host string) (*ssh.Client, error) {
+	authMethod, err := publicKey(AccessKey)
+	if err != nil {
+		return nil, ReturnLogError("failed to get public key: %v", err)
+	}
+
+	callback, err := hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &ssh.ClientConfig{
+		User: AwsUser,
+		Auth: []ssh.AuthMethod{
+			authMethod,
+		},
+		HostKeyCallback: callback,
+		Timeout:         sshDialTimeout,
+	}
+	conn, err := ssh.Dial("tcp", host, cfg)
+	if err != nil {
+		return nil, ReturnLogError("failed to dial: %v", err)
+	}
+
+	return conn, nil
+}
+
+// CaptureHostKeys appends the host keys of the nodes not yet on the known hosts file at path.
+//
+// keys already on the file are checked and a mismatch returns an error.
+func CaptureHostKeys(path string, ips ...string) error {
+	var known ssh.HostKeyCallback
+	if _, err := os.Stat(path); err == nil {
+		known, err = knownhosts.New(path)
+		if err != nil {
+			return ReturnLogError("failed to load known hosts %s: %v", path, err)
+		}
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return ReturnLogError("failed to open known hosts %s: %v", path, err)
+	}
+	defer f.Close()
+
+	for _, ip := range ips {
+		line, err := scanHostKey(net.JoinHostPort(ip, sshPort), known)
+		if err != nil {
+			return err
+		}
+		if line == "" {
+			continue
+		}
+
+		if _, err = f.WriteString(line + "\n"); err != nil {
+			return ReturnLogError("failed to write known hosts %s: %v", path, err)
+		}
+	}
+
+	return nil
+}
+
+// scanHostKey returns the known hosts line for the host key or empty when the key is already known.
+//
+// the handshake is aborted right after the host key is received, so no credentials are needed.
+func scanHostKey(host string, known ssh.HostKeyCallback) (string, error) {
+	var line string
+	var keyErr error
+	received := false
+
+	cfg := &ssh.ClientConfig{
+		User: AwsUser,
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			received = true
+			if known != nil {
+				err := known(hostname, remote, key)
+				var ke *knownhosts.KeyError
+				if err == nil {
+					return errors.New("host key already known")
+				}
+				if !errors.As(err, &ke) || len(ke.Want) > 0 {
+					keyErr = err
+					return err
+				}
+			}
+			line = knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key)
+
+			return errors.New("host key captured")
+		},
+		Timeout: sshDialTimeout,
+	}
+
+	conn, err := ssh.Dial("tcp", host, cfg)
+	if err == nil {
+		_ = conn.Close()
+	}
+
+	switch {
+	case keyErr != nil:
+		return "", ReturnLogError("host key mismatch for %s: %v", host, keyErr)
+	case !received:
+		return "", ReturnLogError("failed to get host key from %s: %v", host, err)
+	}
+
+	return line, nil
+}
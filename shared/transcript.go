@@ -0,0 +1,71 @@
+package shared
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+)
+
+// maxTranscriptOutput is the max length kept of stdout and stderr for each recorded command.
+const maxTranscriptOutput = 4096
+
+var transcript struct {
+	sync.Mutex
+	results []CmdResult
+}
+
+// recordCommand keeps the redacted result of a command run by the executors for the reports.
+func recordCommand(res *CmdResult) {
+	if res == nil {
+		return
+	}
+
+	recorded := *res
+	recorded.Cmd = Redact(recorded.Cmd)
+	recorded.Stdout = truncate(Redact(recorded.Stdout))
+	recorded.Stderr = truncate(Redact(recorded.Stderr))
+
+	transcript.Lock()
+	defer transcript.Unlock()
+	transcript.results = append(transcript.results, recorded)
+}
+
+// TakeTranscript returns the commands run since the last call and resets the transcript.
+func TakeTranscript() []CmdResult {
+	transcript.Lock()
+	defer transcript.Unlock()
+
+	results := transcript.results
+	transcript.results = nil
+
+	return results
+}
+
+func truncate(s string) string {
+	if len(s) <= maxTranscriptOutput {
+		return s
+	}
+
+	return s[:maxTranscriptOutput] + "...(truncated)"
+}
+
+// ArtifactDir returns the directory for reports from config, defaulting to artifacts on the project root.
+func ArtifactDir() (string, error) {
+	cfg, err := GetConfig()
+	if err != nil {
+		return "", err
+	}
+
+	dir := cfg.ArtifactDir
+	if dir == "" {
+		_, b, _, _ := runtime.Caller(0)
+		dir = filepath.Join(filepath.Dir(b), "..", "artifacts")
+	}
+
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return "", ReturnLogError("failed to create artifact dir %s: %v", dir, err)
+	}
+
+	return dir, nil
+}